@@ -0,0 +1,70 @@
+package kdbush
+
+// RangeCount returns number of points across [minX], [minY], [maxX], [maxY] without materializing indexes.
+// Nodes that lie entirely inside the query are counted without testing their points
+func (kd *KDBushOf[C, I]) RangeCount(minX, minY, maxX, maxY float64) int {
+	count := 0
+
+	kd.walk(func(bMinX, bMinY, bMaxX, bMaxY float64) int {
+		return classifyRange(minX, minY, maxX, maxY, bMinX, bMinY, bMaxX, bMaxY)
+	}, func(x, y float64) bool {
+		return x >= minX && x <= maxX && y >= minY && y <= maxY
+	}, func(i int, x, y float64) bool {
+		count++
+		return true
+	}, func(left, right int) bool {
+		count += right - left + 1 - kd.deletedBetween(left, right)
+		return true
+	})
+
+	return count
+}
+
+// WithinCount returns number of points within radius of given single [Point] without materializing indexes.
+// Nodes that lie entirely inside the radius are counted without testing their points
+func (kd *KDBushOf[C, I]) WithinCount(qx, qy float64, radius float64) int {
+	count := 0
+	r2 := radius * radius
+
+	kd.walk(func(minX, minY, maxX, maxY float64) int {
+		if boxDist(qx, qy, minX, minY, maxX, maxY) > r2 {
+			return boxOutside
+		}
+		if farthestBoxDist(qx, qy, minX, minY, maxX, maxY) <= r2 {
+			return boxInside
+		}
+		return boxPartial
+	}, func(x, y float64) bool {
+		return sqrtDist(x, y, qx, qy) <= r2
+	}, func(i int, x, y float64) bool {
+		count++
+		return true
+	}, func(left, right int) bool {
+		count += right - left + 1 - kd.deletedBetween(left, right)
+		return true
+	})
+
+	return count
+}
+
+// RangeReduce folds every point across [minX], [minY], [maxX], [maxY] into single value, starting from init.
+// It is a function since Go methods can't have their own type parameter
+func RangeReduce[T any, C Coord, I ID](kd *KDBushOf[C, I], minX, minY, maxX, maxY float64, init T, fn func(acc T, id I, x, y float64) T) T {
+	acc := init
+	kd.RangeFunc(minX, minY, maxX, maxY, func(id I, x, y float64) bool {
+		acc = fn(acc, id, x, y)
+		return true
+	})
+	return acc
+}
+
+// WithinReduce folds every point within radius of given single [Point] into single value, starting from init.
+// It is a function since Go methods can't have their own type parameter
+func WithinReduce[T any, C Coord, I ID](kd *KDBushOf[C, I], qx, qy float64, radius float64, init T, fn func(acc T, id I, x, y float64) T) T {
+	acc := init
+	kd.WithinFunc(qx, qy, radius, func(id I, x, y float64) bool {
+		acc = fn(acc, id, x, y)
+		return true
+	})
+	return acc
+}
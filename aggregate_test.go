@@ -0,0 +1,78 @@
+package kdbush_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test RangeCount & WithinCount against Range & Within
+func TestCount(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	_points := []kdbush.Point{}
+	for i := 0; i < 10_000; i++ {
+		_points = append(_points, &kdbush.SimplePoint{rng.Float64() * 100, rng.Float64() * 100})
+	}
+
+	for _, nodeSize := range []int{kdbush.STANDARD_NODE_SIZE, 4} {
+		bush := kdbush.NewBush().BuildIndex(_points, nodeSize)
+
+		// delete some points, so counted node should skip them
+		for i := 0; i < len(_points); i += 7 {
+			bush.Delete(i)
+		}
+
+		for q := 0; q < 50; q++ {
+			minX, minY := rng.Float64()*100, rng.Float64()*100
+			maxX, maxY := minX+rng.Float64()*50, minY+rng.Float64()*50
+			assert.Equal(t, bush.RangeCount(minX, minY, maxX, maxY), len(bush.Range(minX, minY, maxX, maxY)), "range count should be same")
+
+			qx, qy, r := rng.Float64()*100, rng.Float64()*100, rng.Float64()*50
+			assert.Equal(t, bush.WithinCount(qx, qy, r), len(bush.Within(qx, qy, r)), "within count should be same")
+		}
+
+		assert.Equal(t, bush.RangeCount(-1, -1, 101, 101), bush.Live(), "it should count all live points")
+		assert.Equal(t, bush.WithinCount(50, 50, 100), bush.Live(), "it should count all live points")
+		assert.Equal(t, bush.RangeCount(200, 200, 300, 300), 0)
+	}
+
+	assert.Equal(t, kdbush.NewBush().RangeCount(0, 0, 1, 1), 0, "it should be empty when not indexed")
+	assert.Equal(t, kdbush.NewBush().BuildIndex([]kdbush.Point{}, 4).WithinCount(0, 0, 1), 0)
+}
+
+// Test RangeReduce & WithinReduce
+func TestReduce(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, 10)
+
+	sum := kdbush.RangeReduce(bush, -3, -2, 5, 7, 0, func(acc int, id int, x, y float64) int {
+		return acc + id
+	})
+	expected := 0
+	for _, id := range bush.Range(-3, -2, 5, 7) {
+		expected += id
+	}
+	assert.Equal(t, sum, expected)
+	assert.NotZero(t, sum)
+
+	maxX := kdbush.WithinReduce(bush, 1, 1, 4, -100.0, func(acc float64, id int, x, y float64) float64 {
+		if x > acc {
+			return x
+		}
+		return acc
+	})
+	expectedMaxX := -100.0
+	for _, id := range bush.Within(1, 1, 4) {
+		if points[id].GetX() > expectedMaxX {
+			expectedMaxX = points[id].GetX()
+		}
+	}
+	assert.Equal(t, maxX, expectedMaxX)
+	assert.Equal(t, maxX, 5.0)
+
+	assert.Equal(t, kdbush.RangeReduce(bush, 20, 20, 30, 30, "empty", func(acc string, id int, x, y float64) string {
+		return "visited"
+	}), "empty", "it should return init when nothing found")
+}
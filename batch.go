@@ -0,0 +1,77 @@
+package kdbush
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// batchChunkSize number of queries taken by a worker at once
+const batchChunkSize = 64
+
+// batchArenaSize initial size of worker result buffer
+const batchArenaSize = 1024
+
+// RangeBatch runs [KDBushOf.Range] for each box [minX, minY, maxX, maxY] concurrently using up to [workers] goroutines,
+// and returns the indexes of each box in the same order. Use 0 or less on [workers] for GOMAXPROCS
+func (kd *KDBushOf[C, I]) RangeBatch(boxes [][4]float64, workers int) [][]I {
+	results := make([][]I, len(boxes))
+
+	kd.batch(len(boxes), workers, results, func(i int, arena []I) []I {
+		return kd.RangeAppend(arena, boxes[i][0], boxes[i][1], boxes[i][2], boxes[i][3])
+	})
+
+	return results
+}
+
+// WithinBatch runs [KDBushOf.Within] for each center [x, y] with radius of the same position concurrently using up to [workers] goroutines,
+// and returns the indexes of each center in the same order. Use 0 or less on [workers] for GOMAXPROCS.
+// It panics if length of centers and radii are different
+func (kd *KDBushOf[C, I]) WithinBatch(centers [][2]float64, radii []float64, workers int) [][]I {
+	if len(centers) != len(radii) {
+		panic("kdbush: centers and radii length must be same")
+	}
+
+	results := make([][]I, len(centers))
+
+	kd.batch(len(centers), workers, results, func(i int, arena []I) []I {
+		return kd.WithinAppend(arena, centers[i][0], centers[i][1], radii[i])
+	})
+
+	return results
+}
+
+// batch runs n queries on a bounded pool of workers and stores the result of i-th query into results[i].
+// Each worker appends results into its own buffer, so results share few large allocations instead of one per query
+func (kd *KDBushOf[C, I]) batch(n, workers int, results [][]I, query func(i int, arena []I) []I) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, (n+batchChunkSize-1)/batchChunkSize)
+
+	var next int64
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			arena := make([]I, 0, batchArenaSize)
+			for {
+				start := int(atomic.AddInt64(&next, batchChunkSize)) - batchChunkSize
+				if start >= n {
+					return
+				}
+
+				for i := start; i < min(start+batchChunkSize, n); i++ {
+					offset := len(arena)
+					arena = query(i, arena)
+					results[i] = arena[offset:len(arena):len(arena)]
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
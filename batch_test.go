@@ -0,0 +1,47 @@
+package kdbush_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test RangeBatch & WithinBatch give same result with Range & Within
+func TestBatch(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	_points := []kdbush.Point{}
+	for i := 0; i < 10_000; i++ {
+		_points = append(_points, &kdbush.SimplePoint{rng.Float64() * 100, rng.Float64() * 100})
+	}
+	bush := kdbush.NewBush().BuildIndex(_points, 8)
+
+	boxes := [][4]float64{}
+	centers := [][2]float64{}
+	radii := []float64{}
+	for i := 0; i < 1_000; i++ {
+		x, y := rng.Float64()*120-10, rng.Float64()*120-10
+		boxes = append(boxes, [4]float64{x, y, x + rng.Float64()*10, y + rng.Float64()*10})
+		centers = append(centers, [2]float64{x, y})
+		radii = append(radii, rng.Float64()*5)
+	}
+
+	for _, workers := range []int{0, 1, 4} {
+		ranges := bush.RangeBatch(boxes, workers)
+		assert.Equal(t, len(ranges), len(boxes))
+		for i, box := range boxes {
+			assert.Equal(t, ranges[i], bush.Range(box[0], box[1], box[2], box[3]), "range result should be same")
+		}
+
+		withins := bush.WithinBatch(centers, radii, workers)
+		assert.Equal(t, len(withins), len(centers))
+		for i, center := range centers {
+			assert.Equal(t, withins[i], bush.Within(center[0], center[1], radii[i]), "within result should be same")
+		}
+	}
+
+	assert.Equal(t, bush.RangeBatch(nil, 4), [][]int{})
+	assert.Panics(t, func() { bush.WithinBatch(centers, radii[1:], 4) })
+}
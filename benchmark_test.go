@@ -128,3 +128,33 @@ func BenchmarkWithin(b *testing.B) {
 		})
 	}
 }
+
+// Benchmark BuildIndexParallel func
+func BenchmarkBuildIndexParallel(b *testing.B) {
+	var rng = rand.New(rand.NewSource(1))
+
+	var points = []struct {
+		Points   []kdbush.Point
+		Total    int
+		NodeSize int
+	}{
+		{Points: []kdbush.Point{}, NodeSize: kdbush.STANDARD_NODE_SIZE, Total: 100_000},
+		{Points: []kdbush.Point{}, NodeSize: kdbush.STANDARD_NODE_SIZE, Total: 1_000_000},
+	}
+
+	// Setup
+	for num := range points {
+		for i := 0; i < points[num].Total; i++ {
+			points[num].Points = append(points[num].Points, &kdbush.SimplePoint{rng.Float64()*24.0 + 24.0, rng.Float64()*24.0 + 24.0})
+		}
+	}
+
+	for _, v := range points {
+		b.Run(fmt.Sprintf("nodeSize_%d_total_%d", v.NodeSize, v.Total), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				kdbush.NewBush().
+					BuildIndexParallel(v.Points, v.NodeSize, 0)
+			}
+		})
+	}
+}
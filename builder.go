@@ -0,0 +1,88 @@
+package kdbush
+
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+// NewBushWithCapacity return a new pointer of [KDBush] in builder mode, preallocated for numItems points.
+// Add points one by one with [KDBushOf.Add], then call [KDBushOf.Finish] to build the index
+func NewBushWithCapacity(numItems, nodeSize int) *KDBush {
+	return NewBushOfWithCapacity[float64, int](numItems, nodeSize)
+}
+
+// NewBushOfWithCapacity same as [NewBushWithCapacity] with given coordinate & index type
+func NewBushOfWithCapacity[C Coord, I ID](numItems, nodeSize int) *KDBushOf[C, I] {
+	kd := KDBushOf[C, I]{
+		nodeSize:     nodeSize,
+		ids:          make([]I, 0, numItems),
+		coords:       make([]C, 0, 2*numItems),
+		compactRatio: STANDARD_COMPACT_RATIO,
+	}
+	return &kd
+}
+
+// Add add a point into builder and return its index (sequential, starting from 0 or after the last index).
+// Adding more points than capacity is allowed, the buffers will grow like append.
+// Index is not usable until [KDBushOf.Finish] is called.
+// Add can't be mixed with ids that can't fit in int (e.g. uint64 ids from [KDBushOf.BuildIndexWithIDs]),
+// it panics when the last index is too large to continue or the new index can't fit in I
+func (kd *KDBushOf[C, I]) Add(x, y float64) int {
+	if kd.nextID < 0 {
+		// unknown after loaded, continue after the last index
+		nextID := 0
+		for _, v := range kd.ids {
+			if v > 0 && uint64(v) >= math.MaxInt {
+				panic("kdbush: id is too large to continue with Add")
+			}
+			nextID = max(nextID, int(v)+1)
+		}
+		kd.nextID = nextID
+	}
+
+	id := kd.nextID
+	if !fitsID[I](uint64(id)) {
+		panic("kdbush: id of Add can't fit in the index type")
+	}
+
+	kd.indexed = false
+	kd.nextID++
+
+	kd.setPosition(id, len(kd.ids))
+	kd.ids = append(kd.ids, I(id))
+	kd.coords = append(kd.coords, C(x), C(y))
+	return id
+}
+
+// Finish build kd-tree index of all added points, deleted points are removed
+func (kd *KDBushOf[C, I]) Finish() *KDBushOf[C, I] {
+	kd.unmap()
+	kd.removeDeleted()
+	sort(kd.ids, kd.coords, kd.nodeSize, 0, len(kd.ids)-1, 0, 2)
+	kd.computeBounds()
+
+	kd.indexed = true
+	return kd
+}
+
+// FinishParallel same as [KDBushOf.Finish], but sort subtrees using up to [workers] goroutines.
+// Use 0 or less on [workers] for GOMAXPROCS. The index is identical with [KDBushOf.Finish]
+func (kd *KDBushOf[C, I]) FinishParallel(workers int) *KDBushOf[C, I] {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	kd.unmap()
+	kd.removeDeleted()
+
+	// the calling goroutine is one of workers
+	sem := make(chan struct{}, workers-1)
+	wg := sync.WaitGroup{}
+	sortParallel(kd.ids, kd.coords, kd.nodeSize, 0, len(kd.ids)-1, 0, 2, sem, &wg)
+	wg.Wait()
+	kd.computeBounds()
+
+	kd.indexed = true
+	return kd
+}
@@ -0,0 +1,74 @@
+package kdbush_test
+
+import (
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test builder mode give same index with BuildIndex
+func TestBuilder(t *testing.T) {
+	expected := kdbush.NewBush().BuildIndex(points, 4)
+
+	bush := kdbush.NewBushWithCapacity(len(points), 4)
+	for i, p := range points {
+		assert.Equal(t, bush.Add(p.GetX(), p.GetY()), i, "it should return sequential index")
+	}
+	assert.Equal(t, bush.Indexed(), false, "should not indexed before finish")
+	assert.Equal(t, bush.Range(-10, -10, 10, 10), []int{}, "should return empty slice of int")
+
+	bush.Finish()
+	assert.Equal(t, bush.Indexed(), true, "should indexed")
+	assert.Equal(t, bush.GetNodeSize(), 4, "nodesize should be same")
+	assert.Equal(t, bush.GetIndexes(), expected.GetIndexes(), "indexes should be same")
+	assert.Equal(t, bush.GetCoords(), expected.GetCoords(), "coords should be same")
+
+	// adding more than capacity & after finish
+	id := bush.Add(100, 100)
+	assert.Equal(t, id, len(points))
+	assert.Equal(t, bush.Indexed(), false, "should not indexed after add")
+	bush.Finish()
+	assert.Equal(t, bush.Range(99, 99, 101, 101), []int{id})
+	assert.Equal(t, len(bush.Range(-2.1, 0, 2.1, 0)), 5)
+
+	// generic builder
+	float32Bush := kdbush.NewBushOfWithCapacity[float32, uint32](2, kdbush.STANDARD_NODE_SIZE)
+	float32Bush.Add(1, 1)
+	float32Bush.Add(2, 2)
+	assert.Equal(t, float32Bush.Finish().Within(2, 2, 0.5), []uint32{1})
+}
+
+// Test BuildIndexFromCoords & BuildIndexFromXY give same index with BuildIndex
+func TestBuildIndexFromCoords(t *testing.T) {
+	expected := kdbush.NewBush().BuildIndex(points, 4)
+
+	coords := []float64{}
+	xs := []float64{}
+	ys := []float64{}
+	for _, p := range points {
+		coords = append(coords, p.GetX(), p.GetY())
+		xs = append(xs, p.GetX())
+		ys = append(ys, p.GetY())
+	}
+	original := append([]float64(nil), coords...)
+
+	bush := kdbush.NewBush().BuildIndexFromCoords(coords, 4)
+	assert.Equal(t, bush.GetIndexes(), expected.GetIndexes(), "indexes should be same")
+	assert.Equal(t, bush.GetCoords(), expected.GetCoords(), "coords should be same")
+	assert.Equal(t, coords, original, "coords should not be modified")
+
+	bush = kdbush.NewBush().BuildIndexFromXY(xs, ys, 4)
+	assert.Equal(t, bush.GetIndexes(), expected.GetIndexes(), "indexes should be same")
+	assert.Equal(t, bush.GetCoords(), expected.GetCoords(), "coords should be same")
+
+	bush = kdbush.NewBush().BuildIndexFromCoordsInPlace(coords, 4)
+	assert.Equal(t, bush.GetIndexes(), expected.GetIndexes(), "indexes should be same")
+	assert.Equal(t, coords, expected.GetCoords(), "coords should be reordered in place")
+
+	int16Bush := kdbush.NewBushOf[int16, uint16]().BuildIndexFromXY([]int16{1, 2, 3}, []int16{4, 5, 6}, 4)
+	assert.Equal(t, int16Bush.Range(2, 5, 3, 6), []uint16{1, 2})
+
+	assert.Panics(t, func() { kdbush.NewBush().BuildIndexFromCoords([]float64{1, 2, 3}, 4) })
+	assert.Panics(t, func() { kdbush.NewBush().BuildIndexFromXY([]float64{1, 2}, []float64{1}, 4) })
+}
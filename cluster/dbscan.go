@@ -0,0 +1,142 @@
+// Package cluster implements density & distance based clustering of points in KDBush index
+package cluster
+
+import (
+	"github.com/raditzlawliet/kdbush"
+	"github.com/raditzlawliet/kdbush/geo"
+)
+
+// Noise label of point that does not belong to any cluster, also used for deleted points
+const Noise = -1
+
+// unvisited label of point that not visited yet, never returned
+const unvisited = -2
+
+// DBSCAN cluster points using Density-Based Spatial Clustering of Applications with Noise.
+// Neighbourhood of a point is all points within [eps], point with at least [minPts] neighbours (including itself) is a core point.
+// Return label of each point indexed by its position in [kdbush.KDBushOf.GetIndexes], clusters are numbered from 0 and [Noise] for outliers.
+// Use [LabelsByID] to look labels up by id
+func DBSCAN[C kdbush.Coord, I kdbush.ID](kd *kdbush.KDBushOf[C, I], eps float64, minPts int) []int {
+	return dbscan(kd, minPts, func(x, y float64, dst []I) []I {
+		return kd.WithinAppend(dst, x, y, eps)
+	})
+}
+
+// GeoDBSCAN same as [DBSCAN] for locations (lng, lat), with [epsInKm] in kilometers of great circle distance
+func GeoDBSCAN[C kdbush.Coord, I kdbush.ID](kd *kdbush.KDBushOf[C, I], epsInKm float64, minPts int) []int {
+	return dbscan(kd, minPts, func(lng, lat float64, dst []I) []I {
+		geo.AroundFunc(kd, lng, lat, epsInKm, nil, func(id I, distInKm float64) bool {
+			dst = append(dst, id)
+			return true
+		})
+		return dst
+	})
+}
+
+// dbscan cluster points with [neighbours] appending ids of all points in neighbourhood of a location into dst
+func dbscan[C kdbush.Coord, I kdbush.ID](kd *kdbush.KDBushOf[C, I], minPts int, neighbours func(x, y float64, dst []I) []I) []int {
+	labels := newLabels(kd, unvisited)
+	positionOf := positionsOf(kd)
+	coords := kd.GetCoords()
+
+	cluster := 0
+	var queue, buf []I
+
+	for i := range kd.GetIndexes() {
+		if kd.DeletedAt(i) || labels[i] != unvisited {
+			continue
+		}
+
+		queue = neighbours(float64(coords[2*i]), float64(coords[2*i+1]), queue[:0])
+		if len(queue) < minPts {
+			labels[i] = Noise
+			continue
+		}
+
+		// expand new cluster from core point
+		labels[i] = cluster
+		for len(queue) > 0 {
+			p := positionOf(queue[len(queue)-1])
+			queue = queue[:len(queue)-1] // .pop()
+
+			if labels[p] == Noise {
+				// border point
+				labels[p] = cluster
+				continue
+			}
+			if labels[p] != unvisited {
+				continue
+			}
+
+			labels[p] = cluster
+			buf = neighbours(float64(coords[2*p]), float64(coords[2*p+1]), buf[:0])
+			if len(buf) >= minPts {
+				queue = append(queue, buf...)
+			}
+		}
+		cluster++
+	}
+
+	// never visited, e.g. deleted points
+	for i, label := range labels {
+		if label == unvisited {
+			labels[i] = Noise
+		}
+	}
+
+	return labels
+}
+
+// newLabels return labels indexed by position in the index filled with [label]
+func newLabels[C kdbush.Coord, I kdbush.ID](kd *kdbush.KDBushOf[C, I], label int) []int {
+	labels := make([]int, len(kd.GetIndexes()))
+	for i := range labels {
+		labels[i] = label
+	}
+	return labels
+}
+
+// positionsOf return lookup of position in the index by id. Dense ids (e.g. [kdbush.KDBushOf.BuildIndex]) use inverse permutation,
+// others (e.g. [kdbush.KDBushOf.BuildIndexWithIDs]) use map
+func positionsOf[C kdbush.Coord, I kdbush.ID](kd *kdbush.KDBushOf[C, I]) func(id I) int {
+	ids := kd.GetIndexes()
+
+	dense := true
+	size := 0
+	for _, id := range ids {
+		if id < 0 || uint64(id) >= uint64(2*len(ids)) {
+			dense = false
+			break
+		}
+		if int(id) >= size {
+			size = int(id) + 1
+		}
+	}
+
+	if dense {
+		inverse := make([]int, size)
+		for i, id := range ids {
+			inverse[id] = i
+		}
+		return func(id I) int {
+			return inverse[id]
+		}
+	}
+
+	positions := make(map[I]int, len(ids))
+	for i, id := range ids {
+		positions[id] = i
+	}
+	return func(id I) int {
+		return positions[id]
+	}
+}
+
+// LabelsByID return labels of [DBSCAN] or [Threshold] keyed by id of each point, e.g. ids of [kdbush.KDBushOf.BuildIndexWithIDs]
+func LabelsByID[C kdbush.Coord, I kdbush.ID](kd *kdbush.KDBushOf[C, I], labels []int) map[I]int {
+	result := make(map[I]int, len(labels))
+	for i, id := range kd.GetIndexes() {
+		result[id] = labels[i]
+	}
+	return result
+}
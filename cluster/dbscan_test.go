@@ -0,0 +1,144 @@
+package cluster_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/raditzlawliet/kdbush/cluster"
+	"github.com/raditzlawliet/kdbush/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+// blobs return points of 2 dense blobs around (10, 10) & (50, 50), and a few outliers far from both
+func blobs() []kdbush.Point {
+	var rng = rand.New(rand.NewSource(1))
+
+	points := []kdbush.Point{}
+	for _, center := range [][2]float64{{10, 10}, {50, 50}} {
+		for i := 0; i < 200; i++ {
+			points = append(points, &kdbush.SimplePoint{X: center[0] + rng.NormFloat64(), Y: center[1] + rng.NormFloat64()})
+		}
+	}
+	points = append(points, &kdbush.SimplePoint{X: 30, Y: 30}, &kdbush.SimplePoint{X: 90, Y: 10}, &kdbush.SimplePoint{X: 10, Y: 90})
+	return points
+}
+
+// Test DBSCAN on blobs
+func TestDBSCAN(t *testing.T) {
+	points := blobs()
+	bush := kdbush.NewBush().BuildIndex(points, 8)
+
+	positional := cluster.DBSCAN(bush, 1, 5)
+	assert.Equal(t, len(positional), len(points))
+	labels := cluster.LabelsByID(bush, positional)
+	for i, id := range bush.GetIndexes() {
+		assert.Equal(t, labels[id], positional[i], "labels should be indexed by position")
+	}
+
+	// outliers are noise
+	assert.Equal(t, between(labels, 400, 403), []int{cluster.Noise, cluster.Noise, cluster.Noise})
+
+	// each blob is mostly a single cluster, and blobs are different clusters
+	assert.NotEqual(t, majority(between(labels, 0, 200)), majority(between(labels, 200, 400)))
+	assert.NotEqual(t, majority(between(labels, 0, 200)), cluster.Noise)
+	assert.NotEqual(t, majority(between(labels, 200, 400)), cluster.Noise)
+
+	// every core point must be in the same cluster with all of its neighbours
+	for i, p := range points {
+		neighbours := bush.Within(p.GetX(), p.GetY(), 1)
+		if len(neighbours) < 5 {
+			continue
+		}
+		for _, n := range neighbours {
+			assert.Equal(t, labels[n], labels[i], "neighbours of core point should be in the same cluster")
+		}
+	}
+
+	// big eps merge everything into single cluster
+	for _, label := range cluster.DBSCAN(bush, 100, 1) {
+		assert.Equal(t, label, 0)
+	}
+}
+
+// Test DBSCAN skip deleted points
+func TestDBSCANWithDeleted(t *testing.T) {
+	points := blobs()
+	bush := kdbush.NewBush().BuildIndex(points, 8)
+	bush.Delete(0)
+
+	labels := cluster.LabelsByID(bush, cluster.DBSCAN(bush, 1, 5))
+	assert.Equal(t, labels[0], cluster.Noise, "deleted point should be noise")
+	assert.Equal(t, len(cluster.DBSCAN(kdbush.NewBush().BuildIndex([]kdbush.Point{}, 8), 1, 5)), 0)
+}
+
+// Test GeoDBSCAN
+func TestGeoDBSCAN(t *testing.T) {
+	points := []kdbush.Point{
+		// Surabaya
+		&geo.MarkerPoint{Lat: -7.265850333832262, Lng: 112.74996851603348},
+		&geo.MarkerPoint{Lat: -7.261669467066506, Lng: 112.74641761874226},
+		&geo.MarkerPoint{Lat: -7.262083358514896, Lng: 112.74242319159997},
+		&geo.MarkerPoint{Lat: -7.266374558931883, Lng: 112.74432953003436},
+		&geo.MarkerPoint{Lat: -7.271393029465542, Lng: 112.74264315372703},
+		// Surabaya but not near from previous one
+		&geo.MarkerPoint{Lat: -7.279393373923168, Lng: 112.7413233810989},
+		// Jakarta
+		&geo.MarkerPoint{Lat: -6.199482563158932, Lng: 106.84831233134457},
+		&geo.MarkerPoint{Lat: -6.173354331560208, Lng: 106.82685482999992},
+	}
+	bush := kdbush.NewBush().BuildIndex(points, kdbush.STANDARD_NODE_SIZE)
+
+	assert.Equal(t, between(cluster.LabelsByID(bush, cluster.GeoDBSCAN(bush, 0.8, 3)), 0, 8), []int{0, 0, 0, 0, 0, cluster.Noise, cluster.Noise, cluster.Noise})
+	assert.Equal(t, between(cluster.LabelsByID(bush, cluster.GeoDBSCAN(bush, 5, 2)), 0, 8), []int{0, 0, 0, 0, 0, 0, 1, 1})
+}
+
+// Test DBSCAN with arbitrary ids from BuildIndexWithIDs
+func TestDBSCANWithIDs(t *testing.T) {
+	points := blobs()
+	ids := make([]uint64, len(points))
+	for i := range ids {
+		ids[i] = uint64(1)<<63 + uint64(i)*1e12
+	}
+	bush := kdbush.NewBushOf[float64, uint64]().BuildIndexWithIDs(points, ids, 8)
+
+	dense := kdbush.NewBush().BuildIndex(points, 8)
+	expected := cluster.LabelsByID(dense, cluster.DBSCAN(dense, 1, 5))
+	labels := cluster.LabelsByID(bush, cluster.DBSCAN(bush, 1, 5))
+	assert.Equal(t, len(labels), len(points))
+	for i, id := range ids {
+		assert.Equal(t, labels[id], expected[i], "it should be same clusters with dense ids")
+	}
+
+	negative := make([]int, len(points))
+	for i := range negative {
+		negative[i] = -i - 1
+	}
+	negativeBush := kdbush.NewBush().BuildIndexWithIDs(points, negative, 8)
+	labels2 := cluster.LabelsByID(negativeBush, cluster.DBSCAN(negativeBush, 1, 5))
+	for i, id := range negative {
+		assert.Equal(t, labels2[id], expected[i], "it should be same clusters with negative ids")
+	}
+}
+
+// between return labels of ids from [from] until before [to]
+func between(labels map[int]int, from, to int) []int {
+	result := []int{}
+	for id := from; id < to; id++ {
+		result = append(result, labels[id])
+	}
+	return result
+}
+
+// majority return most frequent label
+func majority(labels []int) int {
+	count := map[int]int{}
+	result := labels[0]
+	for _, label := range labels {
+		count[label]++
+		if count[label] > count[result] {
+			result = label
+		}
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package cluster
+
+import (
+	"sort"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/raditzlawliet/kdbush/geo"
+)
+
+// Threshold cluster points by distance threshold (single-linkage), points within [dist] of each other are in the same cluster transitively.
+// Same as [DBSCAN] with minPts 1, but built on [kdbush.SelfJoin]. Return label of each point indexed by its position in [kdbush.KDBushOf.GetIndexes], clusters are numbered from 0
+func Threshold[C kdbush.Coord, I kdbush.ID](kd *kdbush.KDBushOf[C, I], dist float64) []int {
+	return threshold(kd, func(fn func(ia, ib I)) {
+		kdbush.SelfJoin(kd, dist, func(ia, ib I, d2 float64) bool {
+			fn(ia, ib)
+			return true
+		})
+	})
+}
+
+// GeoThreshold same as [Threshold] for locations (lng, lat), with [distInKm] in kilometers of great circle distance
+func GeoThreshold[C kdbush.Coord, I kdbush.ID](kd *kdbush.KDBushOf[C, I], distInKm float64) []int {
+	return threshold(kd, func(fn func(ia, ib I)) {
+		geo.SelfJoin(kd, distInKm, func(ia, ib I, distInKm float64) bool {
+			fn(ia, ib)
+			return true
+		})
+	})
+}
+
+// threshold cluster points by merging every pair of points reported by [pairs] using union-find
+func threshold[C kdbush.Coord, I kdbush.ID](kd *kdbush.KDBushOf[C, I], pairs func(fn func(ia, ib I))) []int {
+	ids := kd.GetIndexes()
+	positionOf := positionsOf(kd)
+	parents := newLabels(kd, Noise)
+	for i := range ids {
+		if !kd.DeletedAt(i) {
+			parents[i] = i
+		}
+	}
+
+	// find root of the set with path halving
+	find := func(p int) int {
+		for parents[p] != p {
+			parents[p] = parents[parents[p]]
+			p = parents[p]
+		}
+		return p
+	}
+
+	// root of each set is the point with smallest id, then smallest position for duplicated ids
+	less := func(a, b int) bool {
+		return ids[a] < ids[b] || (ids[a] == ids[b] && a < b)
+	}
+	pairs(func(ia, ib I) {
+		a, b := find(positionOf(ia)), find(positionOf(ib))
+		if less(a, b) {
+			parents[b] = a
+		} else if less(b, a) {
+			parents[a] = b
+		}
+	})
+
+	// number clusters in order of their smallest id
+	roots := []int{}
+	for p, parent := range parents {
+		if parent == p {
+			roots = append(roots, p)
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool { return less(roots[i], roots[j]) })
+
+	labels := newLabels(kd, Noise)
+	for cluster, p := range roots {
+		labels[p] = cluster
+	}
+	for p, parent := range parents {
+		if parent != Noise {
+			labels[p] = labels[find(p)]
+		}
+	}
+
+	return labels
+}
@@ -0,0 +1,73 @@
+package cluster_test
+
+import (
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/raditzlawliet/kdbush/cluster"
+	"github.com/raditzlawliet/kdbush/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test Threshold produce same clusters with DBSCAN with minPts 1
+func TestThreshold(t *testing.T) {
+	points := blobs()
+	bush := kdbush.NewBush().BuildIndex(points, 8)
+
+	for _, dist := range []float64{0.3, 1, 5, 100} {
+		labels := cluster.Threshold(bush, dist)
+		expected := cluster.DBSCAN(bush, dist, 1)
+		assert.Equal(t, len(labels), len(expected))
+
+		// same partition, numbering may be different
+		mapping := map[int]int{}
+		for i := range points {
+			if m, ok := mapping[labels[i]]; ok {
+				assert.Equal(t, m, expected[i], "it should be same partition with DBSCAN")
+			} else {
+				mapping[labels[i]] = expected[i]
+			}
+		}
+		assert.Equal(t, len(mapping), len(uniq(expected)))
+	}
+
+	line := kdbush.NewBush().BuildIndex([]kdbush.Point{
+		&kdbush.SimplePoint{X: 0, Y: 0},
+		&kdbush.SimplePoint{X: 10, Y: 0},
+		&kdbush.SimplePoint{X: 1, Y: 0},
+		&kdbush.SimplePoint{X: 11, Y: 0},
+		&kdbush.SimplePoint{X: 2, Y: 0},
+	}, 1)
+	assert.Equal(t, cluster.LabelsByID(line, cluster.Threshold(line, 1)), map[int]int{0: 0, 1: 1, 2: 0, 3: 1, 4: 0}, "clusters should be numbered in order of their smallest id")
+
+	bush.Delete(0)
+	assert.Equal(t, cluster.LabelsByID(bush, cluster.Threshold(bush, 1))[0], cluster.Noise, "deleted point should be noise")
+
+	// numbered by smallest id, not by position
+	sparse := kdbush.NewBushOf[float64, uint64]().BuildIndexWithIDs([]kdbush.Point{
+		&kdbush.SimplePoint{X: 0, Y: 0},
+		&kdbush.SimplePoint{X: 10, Y: 0},
+		&kdbush.SimplePoint{X: 1, Y: 0},
+	}, []uint64{1 << 63, 5, 1<<64 - 1}, 1)
+	assert.Equal(t, cluster.LabelsByID(sparse, cluster.Threshold(sparse, 1)), map[uint64]int{1 << 63: 1, 5: 0, 1<<64 - 1: 1})
+}
+
+// Test GeoThreshold
+func TestGeoThreshold(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex([]kdbush.Point{
+		&geo.MarkerPoint{Lat: 0, Lng: 179.99},
+		&geo.MarkerPoint{Lat: 0, Lng: 0},
+		&geo.MarkerPoint{Lat: 0, Lng: -179.99},
+	}, kdbush.STANDARD_NODE_SIZE)
+
+	assert.Equal(t, cluster.LabelsByID(bush, cluster.GeoThreshold(bush, 5)), map[int]int{0: 0, 1: 1, 2: 0}, "it should cluster across the antimeridian")
+}
+
+// uniq return distinct values
+func uniq(values []int) map[int]bool {
+	result := map[int]bool{}
+	for _, v := range values {
+		result[v] = true
+	}
+	return result
+}
@@ -0,0 +1,210 @@
+package kdbush
+
+import "math"
+
+// DynamicBush a dynamic spatial index supporting insert, delete & update,
+// using logarithmic method (Bentley–Saxe) over a set of static [KDBush] with power-of-two sizes.
+// Each insert rebuilds O(log n) amortized points and each query fans out over O(log n) indexes.
+//
+// Unlike [KDBush], ids are supplied by caller
+type DynamicBush struct {
+	nodeSize int
+
+	// levels[i] holds at most 2^i points, nil if empty
+	levels []*dynamicLevel
+
+	// location of each id
+	items map[int]dynamicItem
+}
+
+// dynamicLevel single static index of DynamicBush
+type dynamicLevel struct {
+	// bush indexes are slots of ids & deleted
+	bush    *KDBush
+	ids     []int
+	deleted []bool
+	dead    int
+}
+
+// dynamicItem location of id in DynamicBush
+type dynamicItem struct {
+	level int
+	slot  int
+}
+
+// NewDynamicBush return a new pointer of [DynamicBush] with given nodeSize for each index
+func NewDynamicBush(nodeSize int) *DynamicBush {
+	db := DynamicBush{
+		nodeSize: nodeSize,
+		items:    map[int]dynamicItem{},
+	}
+	return &db
+}
+
+// Len return number of points
+func (db *DynamicBush) Len() int {
+	return len(db.items)
+}
+
+// Insert add a point with given id, or move it if the id already exists
+func (db *DynamicBush) Insert(id int, x, y float64) {
+	db.Delete(id)
+
+	ids := []int{id}
+	coords := []float64{x, y}
+
+	// merge all full levels below the first empty level, carry them up like binary counter
+	level := 0
+	for ; level < len(db.levels) && db.levels[level] != nil; level++ {
+		ids, coords = db.levels[level].appendLive(ids, coords)
+		db.levels[level] = nil
+	}
+	if level == len(db.levels) {
+		db.levels = append(db.levels, nil)
+	}
+
+	db.build(level, ids, coords)
+}
+
+// Update move point with given id into new coordinates, same as [DynamicBush.Insert]
+func (db *DynamicBush) Update(id int, x, y float64) {
+	db.Insert(id, x, y)
+}
+
+// Delete remove point with given id, return false if the id does not exist.
+// Level is rebuilt once more than half of its points are deleted
+func (db *DynamicBush) Delete(id int) bool {
+	item, ok := db.items[id]
+	if !ok {
+		return false
+	}
+	delete(db.items, id)
+
+	l := db.levels[item.level]
+	l.deleted[item.slot] = true
+	l.dead++
+
+	if l.dead*2 > len(l.ids) {
+		db.levels[item.level] = nil
+		ids, coords := l.appendLive(nil, nil)
+		if len(ids) > 0 {
+			db.build(item.level, ids, coords)
+		}
+	}
+
+	return true
+}
+
+// Range returns all ids points across [minX], [minY], [maxX], [maxY]
+func (db *DynamicBush) Range(minX, minY, maxX, maxY float64) []int {
+	result := []int{}
+
+	for _, l := range db.levels {
+		if l == nil {
+			continue
+		}
+		l.bush.RangeFunc(minX, minY, maxX, maxY, func(slot int, x, y float64) bool {
+			if !l.deleted[slot] {
+				result = append(result, l.ids[slot])
+			}
+			return true
+		})
+	}
+
+	return result
+}
+
+// Within returns all ids points within radius of given single [Point]
+func (db *DynamicBush) Within(qx, qy float64, radius float64) []int {
+	result := []int{}
+
+	for _, l := range db.levels {
+		if l == nil {
+			continue
+		}
+		l.bush.WithinFunc(qx, qy, radius, func(slot int, x, y float64) bool {
+			if !l.deleted[slot] {
+				result = append(result, l.ids[slot])
+			}
+			return true
+		})
+	}
+
+	return result
+}
+
+// Nearest returns ids of [k] closest points from given single [Point] in order of increasing distance,
+// see [KDBushOf.Nearest]
+func (db *DynamicBush) Nearest(qx, qy float64, k int, maxDist float64, filter func(int) bool) []int {
+	result := []int{}
+	if k == 0 {
+		return result
+	}
+
+	// k nearest of each level, already sorted by distance
+	type candidate struct {
+		id   int
+		dist float64
+	}
+	candidates := [][]candidate{}
+	for _, l := range db.levels {
+		if l == nil {
+			continue
+		}
+		found := []candidate{}
+		l.bush.NearestFunc(qx, qy, maxDist, func(slot int) bool {
+			return !l.deleted[slot] && (filter == nil || filter(l.ids[slot]))
+		}, func(slot int, dist float64) bool {
+			found = append(found, candidate{l.ids[slot], dist})
+			return len(found) != k
+		})
+		candidates = append(candidates, found)
+	}
+
+	// merge closest of all levels
+	for len(result) != k {
+		closest := -1
+		closestDist := math.Inf(1)
+		for i, found := range candidates {
+			if len(found) > 0 && found[0].dist < closestDist {
+				closest = i
+				closestDist = found[0].dist
+			}
+		}
+		if closest < 0 {
+			break
+		}
+
+		result = append(result, candidates[closest][0].id)
+		candidates[closest] = candidates[closest][1:]
+	}
+
+	return result
+}
+
+// build index given ids & coords into level
+func (db *DynamicBush) build(level int, ids []int, coords []float64) {
+	l := dynamicLevel{
+		bush:    NewBush().BuildIndexFromCoordsInPlace(coords, db.nodeSize),
+		ids:     ids,
+		deleted: make([]bool, len(ids)),
+	}
+	db.levels[level] = &l
+
+	for slot, id := range ids {
+		db.items[id] = dynamicItem{level, slot}
+	}
+}
+
+// appendLive append ids & coords of points that not deleted
+func (l *dynamicLevel) appendLive(ids []int, coords []float64) ([]int, []float64) {
+	indexes := l.bush.GetIndexes()
+	lcoords := l.bush.GetCoords()
+	for i, slot := range indexes {
+		if !l.deleted[slot] {
+			ids = append(ids, l.ids[slot])
+			coords = append(coords, lcoords[2*i], lcoords[2*i+1])
+		}
+	}
+	return ids, coords
+}
@@ -0,0 +1,86 @@
+package kdbush_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test DynamicBush with random insert, update & delete against brute force
+func TestDynamicBush(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	bush := kdbush.NewDynamicBush(8)
+	expected := map[int]*kdbush.SimplePoint{}
+
+	for step := 0; step < 5_000; step++ {
+		id := rng.Intn(1_000)
+		switch rng.Intn(4) {
+		case 0:
+			_, ok := expected[id]
+			assert.Equal(t, bush.Delete(id), ok, "delete should return existence")
+			delete(expected, id)
+		case 1:
+			p := &kdbush.SimplePoint{rng.Float64() * 100, rng.Float64() * 100}
+			bush.Update(id, p.X, p.Y)
+			expected[id] = p
+		default:
+			p := &kdbush.SimplePoint{rng.Float64() * 100, rng.Float64() * 100}
+			bush.Insert(id, p.X, p.Y)
+			expected[id] = p
+		}
+
+		if step%250 != 0 {
+			continue
+		}
+
+		assert.Equal(t, bush.Len(), len(expected), "length should be same")
+
+		x, y := rng.Float64()*100, rng.Float64()*100
+		rangeIds, withinIds := []int{}, []int{}
+		for id, p := range expected {
+			if p.X >= x && p.X <= x+20 && p.Y >= y && p.Y <= y+20 {
+				rangeIds = append(rangeIds, id)
+			}
+			if sqDist(p, x, y) <= 15*15 {
+				withinIds = append(withinIds, id)
+			}
+		}
+		assert.ElementsMatch(t, bush.Range(x, y, x+20, y+20), rangeIds, "range result should be same")
+		assert.ElementsMatch(t, bush.Within(x, y, 15), withinIds, "within result should be same")
+
+		nearest := bush.Nearest(x, y, 10, -1, nil)
+		assert.Equal(t, len(nearest), min(10, len(expected)))
+		for i := 1; i < len(nearest); i++ {
+			assert.LessOrEqual(t, sqDist(expected[nearest[i-1]], x, y), sqDist(expected[nearest[i]], x, y), "nearest should be ordered by distance")
+		}
+		farthest := 0.0
+		if len(nearest) > 0 {
+			farthest = sqDist(expected[nearest[len(nearest)-1]], x, y)
+		}
+		closer := 0
+		for _, p := range expected {
+			if sqDist(p, x, y) < farthest {
+				closer++
+			}
+		}
+		assert.Less(t, closer, 10, "nearest should be the closest points")
+	}
+
+	assert.Equal(t, bush.Nearest(0, 0, 0, -1, nil), []int{})
+	assert.Equal(t, bush.Nearest(0, 0, -1, -1, func(id int) bool { return id == 1 }), func() []int {
+		if _, ok := expected[1]; ok {
+			return []int{1}
+		}
+		return []int{}
+	}())
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
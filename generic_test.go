@@ -0,0 +1,70 @@
+package kdbush_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test KDBushOf with different coordinate & index types give same result with KDBush
+func TestGeneric(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, 4)
+
+	float32Bush := kdbush.NewBushOf[float32, uint32]().BuildIndex(points, 4)
+	int32Bush := kdbush.NewBushOf[int32, int]().BuildIndex(points, 4)
+	int8Bush := kdbush.NewBushOf[int8, uint16]().BuildIndex(points, 4)
+
+	assert.Equal(t, len(float32Bush.GetCoords()), len(points)*2, "coords length should 2x with points")
+	assert.Equal(t, len(int8Bush.GetIndexes()), len(points), "indexes length should same with points")
+
+	queries := [][4]float64{{-2.1, 0, 2.1, 0}, {-2.1, 1, 2.1, 2}, {-10, -10, 10, 10}, {3, 3, 3, 3}}
+	for _, q := range queries {
+		expected := bush.Range(q[0], q[1], q[2], q[3])
+		assert.ElementsMatch(t, toInts(float32Bush.Range(q[0], q[1], q[2], q[3])), expected)
+		assert.ElementsMatch(t, int32Bush.Range(q[0], q[1], q[2], q[3]), expected)
+		assert.ElementsMatch(t, toInts(int8Bush.Range(q[0], q[1], q[2], q[3])), expected)
+	}
+
+	for _, q := range [][3]float64{{0, 0, 1}, {0.3, 0.2, 0.8}, {5, 5, 4.5}} {
+		expected := bush.Within(q[0], q[1], q[2])
+		assert.ElementsMatch(t, toInts(float32Bush.Within(q[0], q[1], q[2])), expected)
+		assert.ElementsMatch(t, int32Bush.Within(q[0], q[1], q[2]), expected)
+		assert.ElementsMatch(t, toInts(int8Bush.Within(q[0], q[1], q[2])), expected)
+	}
+}
+
+// Test MarshalBinary & UnmarshalBinary of KDBushOf
+func TestGenericMarshalBinary(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	_points := []kdbush.Point{}
+	for i := 0; i < 1_000; i++ {
+		_points = append(_points, &kdbush.SimplePoint{rng.Float64()*24.0 + 24.0, rng.Float64()*24.0 + 24.0})
+	}
+	bush := kdbush.NewBushOf[float32, uint32]().BuildIndex(_points, 8)
+
+	data, err := bush.MarshalBinary()
+	assert.Nil(t, err)
+	assert.Equal(t, data[1], byte(0x17), "array type should be Float32Array")
+	assert.Equal(t, len(data), 8+1_000*2+1_000*2*4)
+
+	loaded := kdbush.NewBushOf[float32, uint32]()
+	assert.Nil(t, loaded.UnmarshalBinary(data))
+	assert.Equal(t, loaded.GetIndexes(), bush.GetIndexes(), "indexes should be same")
+	assert.Equal(t, loaded.GetCoords(), bush.GetCoords(), "coords should be same")
+
+	// load into different coordinate type
+	converted := kdbush.NewBush()
+	assert.Nil(t, converted.UnmarshalBinary(data))
+	assert.ElementsMatch(t, converted.Range(30, 30, 40, 40), toInts(bush.Range(30, 30, 40, 40)))
+}
+
+func toInts[I kdbush.ID](ids []I) []int {
+	result := make([]int, len(ids))
+	for i, id := range ids {
+		result[i] = int(id)
+	}
+	return result
+}
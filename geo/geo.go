@@ -13,12 +13,32 @@ const (
 	rad         = math.Pi / 180
 )
 
-func Around(bush *kdbush.KDBush, lng, lat float64, maxResults int, maxDistanceInKm float64, predicate func(int) bool) []int {
+// Around returns ids of points closest to given location (lng, lat) in order of increasing distance
+func Around[C kdbush.Coord, I kdbush.ID](bush *kdbush.KDBushOf[C, I], lng, lat float64, maxResults int, maxDistanceInKm float64, predicate func(I) bool) []I {
+	result := []I{}
+
+	around(bush, lng, lat, maxDistanceInKm, predicate, func(id I, _ float64) bool {
+		result = append(result, id)
+		return len(result) != maxResults
+	})
+
+	return result
+}
+
+// AroundFunc calls fn for each point closest to given location (lng, lat) in order of increasing distance, with its distance in kilometers.
+// If fn returns false, AroundFunc stops the search
+func AroundFunc[C kdbush.Coord, I kdbush.ID](bush *kdbush.KDBushOf[C, I], lng, lat float64, maxDistanceInKm float64, predicate func(I) bool, fn func(id I, distInKm float64) bool) {
+	around(bush, lng, lat, maxDistanceInKm, predicate, func(id I, dist float64) bool {
+		return fn(id, 2*earthRadius*math.Asin(math.Sqrt(dist)))
+	})
+}
+
+// around search points closest to given location (lng, lat) in order of increasing distance, calls fn with haversine distance until it returns false
+func around[C kdbush.Coord, I kdbush.ID](bush *kdbush.KDBushOf[C, I], lng, lat float64, maxDistanceInKm float64, predicate func(I) bool, fn func(id I, dist float64) bool) {
 	maxHaverSinDist := 1.0
 	if maxDistanceInKm >= 0 {
 		maxHaverSinDist = haverSin(maxDistanceInKm / earthRadius)
 	}
-	result := []int{}
 
 	// a distance-sorted priority queue that will contain both points and kd-tree q
 	q := geoNodeQueue{}
@@ -47,11 +67,10 @@ func Around(bush *kdbush.KDBush, lng, lat float64, maxResults int, maxDistanceIn
 
 			// add all points of the leaf node to the queue
 			for i := left; i <= right; i++ {
-				itemId := bush.GetIndexes()[i]
-				if predicate == nil || predicate(itemId) {
+				if !bush.DeletedAt(i) && (predicate == nil || predicate(bush.GetIndexes()[i])) {
 					heap.Push(&q, &geoNode{
-						itemID: nullInt{itemId, true},
-						dist:   haverSinDist(lng, lat, bush.GetCoords()[2*i], bush.GetCoords()[2*i+1], cosLat),
+						item: nullInt{i, true},
+						dist: haverSinDist(lng, lat, float64(bush.GetCoords()[2*i]), float64(bush.GetCoords()[2*i+1]), cosLat),
 					})
 				}
 			}
@@ -59,15 +78,14 @@ func Around(bush *kdbush.KDBush, lng, lat float64, maxResults int, maxDistanceIn
 			// not a leaf node (has child nodes)
 
 			mid := (left + right) >> 1 // middle index
-			midLng := bush.GetCoords()[2*mid]
-			midLat := bush.GetCoords()[2*mid+1]
+			midLng := float64(bush.GetCoords()[2*mid])
+			midLat := float64(bush.GetCoords()[2*mid+1])
 
 			// add middle point to the queue
-			itemId := bush.GetIndexes()[mid]
-			if predicate == nil || predicate(itemId) {
+			if !bush.DeletedAt(mid) && (predicate == nil || predicate(bush.GetIndexes()[mid])) {
 				heap.Push(&q, &geoNode{
-					itemID: nullInt{itemId, true},
-					dist:   haverSinDist(lng, lat, midLng, midLat, cosLat),
+					item: nullInt{mid, true},
+					dist: haverSinDist(lng, lat, midLng, midLat, cosLat),
 				})
 			}
 
@@ -115,15 +133,14 @@ func Around(bush *kdbush.KDBush, lng, lat float64, maxResults int, maxDistanceIn
 		}
 
 		// fetch closest points from the queue; they're guaranteed to be closer than all remaining points (both individual and those in kd-tree nodes), since each node's distance is a lower bound of distances to its children
-		for len(q) > 0 && q[0].itemID.Valid {
+		for len(q) > 0 && q[0].item.Valid {
 			candidate := heap.Pop(&q).(*geoNode)
 			if candidate.dist > maxHaverSinDist {
-				return result
+				return
 			}
 
-			result = append(result, candidate.itemID.Int)
-			if len(result) == maxResults {
-				return result
+			if !fn(bush.GetIndexes()[candidate.item.Int], candidate.dist) {
+				return
 			}
 		}
 
@@ -134,6 +151,4 @@ func Around(bush *kdbush.KDBush, lng, lat float64, maxResults int, maxDistanceIn
 			node = nil
 		}
 	}
-
-	return result
 }
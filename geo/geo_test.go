@@ -165,3 +165,23 @@ func TestAroundWithDifferentNodeSize(t *testing.T) {
 		assert.ElementsMatch(t, resultPoints, testCase.ResultPoints, "[%v] Result element point should same", testCase.Name)
 	}
 }
+
+func TestAroundWithDifferentType(t *testing.T) {
+	bush := kdbush.NewBushOf[float32, uint32]().
+		BuildIndex(points, kdbush.STANDARD_NODE_SIZE)
+
+	results := geo.Around(bush, points[7].GetX(), points[7].GetY(), 5, 10, nil)
+	assert.ElementsMatch(t, results, []uint32{6, 7}, "Result element index should same")
+
+	results = geo.Around(bush, points[0].GetX(), points[0].GetY(), -1, -1, func(id uint32) bool { return id != 1 })
+	assert.ElementsMatch(t, results, []uint32{0, 2, 3, 4, 5, 6, 7}, "Result element index should same")
+}
+
+func TestAroundWithDeleted(t *testing.T) {
+	bush := kdbush.NewBush().
+		BuildIndex(points, kdbush.STANDARD_NODE_SIZE)
+	bush.Delete(7)
+
+	results := geo.Around(bush, points[7].GetX(), points[7].GetY(), 5, 10, nil)
+	assert.ElementsMatch(t, results, []int{6}, "Result element index should skip deleted")
+}
@@ -8,8 +8,8 @@ type nullInt struct {
 
 // geoNode for KDBush
 type geoNode struct {
-	// itemID
-	itemID nullInt
+	// item position of point in kd-tree array, invalid for kd-tree node
+	item nullInt
 
 	left   int
 	right  int
@@ -65,3 +65,23 @@ func Distance(lng1, lat1, lng2, lat2 float64) float64 {
 	h := haverSinDist(lng1, lat1, lng2, lat2, math.Cos(lat1*rad))
 	return 2 * earthRadius * math.Asin(math.Sqrt(h))
 }
+
+// boxBoxDist calculate lower bound for haversine distance between points inside two bounding boxes
+func boxBoxDist(a, b *geoNode) float64 {
+	// gap of latitudes, 0 if they overlap
+	dLat := math.Max(0, math.Max(a.minLat-b.maxLat, b.minLat-a.maxLat))
+
+	// gap of longitudes, either directly or across the antimeridian
+	dLng := 0.0
+	if a.maxLng < b.minLng || b.maxLng < a.minLng {
+		direct := math.Max(b.minLng-a.maxLng, a.minLng-b.maxLng)
+		across := 360 - (math.Max(a.maxLng, b.maxLng) - math.Min(a.minLng, b.minLng))
+		dLng = math.Min(direct, across)
+	}
+
+	// cosine of latitude is smallest at the latitude farthest from equator
+	cosA := math.Cos(math.Max(math.Abs(a.minLat), math.Abs(a.maxLat)) * rad)
+	cosB := math.Cos(math.Max(math.Abs(b.minLat), math.Abs(b.maxLat)) * rad)
+
+	return haverSin(dLat*rad) + cosA*cosB*haverSin(dLng*rad)
+}
@@ -0,0 +1,17 @@
+//go:build go1.23
+
+package geo
+
+import (
+	"iter"
+
+	"github.com/raditzlawliet/kdbush"
+)
+
+// AroundSeq returns iterator of ids and distance in kilometers of points closest to given location (lng, lat) in order of increasing distance.
+// Points are searched lazily, stop the iteration to stop the search
+func AroundSeq[C kdbush.Coord, I kdbush.ID](bush *kdbush.KDBushOf[C, I], lng, lat float64, maxDistanceInKm float64, predicate func(I) bool) iter.Seq2[I, float64] {
+	return func(yield func(I, float64) bool) {
+		AroundFunc(bush, lng, lat, maxDistanceInKm, predicate, yield)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build go1.23
+
+package geo_test
+
+import (
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/raditzlawliet/kdbush/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAroundSeq(t *testing.T) {
+	bush := kdbush.NewBush().
+		BuildIndex(points, 4)
+
+	results := []int{}
+	lastDist := 0.0
+	for id, dist := range geo.AroundSeq(bush, points[0].GetX(), points[0].GetY(), -1, nil) {
+		assert.InDelta(t, dist, geo.Distance(points[0].GetX(), points[0].GetY(), points[id].GetX(), points[id].GetY()), 1e-9, "distance should be same")
+		assert.GreaterOrEqual(t, dist, lastDist, "distance should be increasing")
+		lastDist = dist
+		results = append(results, id)
+		if len(results) == 5 {
+			break
+		}
+	}
+	assert.Equal(t, results, geo.Around(bush, points[0].GetX(), points[0].GetY(), 5, -1, nil), "Result should same with Around")
+}
@@ -0,0 +1,121 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/raditzlawliet/kdbush"
+)
+
+// JoinWithin calls fn for each pair of point in a and point in b within distance in kilometers of each other, with their great circle distance in kilometers.
+// Both kd-trees are traversed together, so node pairs farther apart than the distance are pruned at once. If fn returns false, JoinWithin stops the traversal
+func JoinWithin[C1 kdbush.Coord, I1 kdbush.ID, C2 kdbush.Coord, I2 kdbush.ID](a *kdbush.KDBushOf[C1, I1], b *kdbush.KDBushOf[C2, I2], distInKm float64, fn func(ia I1, ib I2, distInKm float64) bool) {
+	join(a, b, distInKm, false, fn)
+}
+
+// SelfJoin calls fn for each pair of points in bush within distance in kilometers of each other, with their great circle distance in kilometers.
+// Each pair is reported once and a point is never paired with itself. If fn returns false, SelfJoin stops the traversal
+func SelfJoin[C kdbush.Coord, I kdbush.ID](bush *kdbush.KDBushOf[C, I], distInKm float64, fn func(ia, ib I, distInKm float64) bool) {
+	join(bush, bush, distInKm, true, fn)
+}
+
+// geoNodePair pair of nodes from both kd-trees for JoinWithin & SelfJoin
+type geoNodePair struct {
+	a geoNode
+	b geoNode
+}
+
+// join traverse both kd-trees together and calls fn for each pair of points within distance.
+// If [self], a and b are the same index, then each pair is visited once by pairing same node only with itself and the following nodes
+func join[C1 kdbush.Coord, I1 kdbush.ID, C2 kdbush.Coord, I2 kdbush.ID](a *kdbush.KDBushOf[C1, I1], b *kdbush.KDBushOf[C2, I2], distInKm float64, self bool, fn func(ia I1, ib I2, distInKm float64) bool) {
+	if !a.Indexed() || !b.Indexed() || distInKm < 0 {
+		return
+	}
+
+	maxHaverSinDist := haverSin(math.Min(distInKm/earthRadius, math.Pi))
+	idsA, coordsA := a.GetIndexes(), a.GetCoords()
+	idsB, coordsB := b.GetIndexes(), b.GetCoords()
+
+	// whole Earth for both kd-trees
+	stack := []geoNodePair{{
+		geoNode{left: 0, right: len(idsA) - 1, axis: 0, minLng: -180, minLat: -90, maxLng: 180, maxLat: 90},
+		geoNode{left: 0, right: len(idsB) - 1, axis: 0, minLng: -180, minLat: -90, maxLng: 180, maxLat: 90},
+	}}
+
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1] // .pop()
+
+		if p.a.left > p.a.right || p.b.left > p.b.right || boxBoxDist(&p.a, &p.b) > maxHaverSinDist {
+			continue
+		}
+
+		// on self join, other pairs than same node are always disjoint
+		same := self && p.a.left == p.b.left && p.a.right == p.b.right
+
+		leafA := p.a.right-p.a.left <= a.GetNodeSize()
+		leafB := p.b.right-p.b.left <= b.GetNodeSize()
+		if leafA && leafB {
+			for i := p.a.left; i <= p.a.right; i++ {
+				if a.DeletedAt(i) {
+					continue
+				}
+				lngA := float64(coordsA[2*i])
+				latA := float64(coordsA[2*i+1])
+				cosLatA := math.Cos(latA * rad)
+
+				j := p.b.left
+				if same {
+					j = i + 1
+				}
+				for ; j <= p.b.right; j++ {
+					d := haverSinDist(lngA, latA, float64(coordsB[2*j]), float64(coordsB[2*j+1]), cosLatA)
+					if d <= maxHaverSinDist && !b.DeletedAt(j) && !fn(idsA[i], idsB[j], 2*earthRadius*math.Asin(math.Sqrt(d))) {
+						return
+					}
+				}
+			}
+			continue
+		}
+
+		if same {
+			// pair each child with itself and the following children
+			children := splitNode(coordsA, p.a)
+			for i := range children {
+				for j := i; j < len(children); j++ {
+					stack = append(stack, geoNodePair{children[i], children[j]})
+				}
+			}
+			continue
+		}
+
+		// split the bigger node, the other one is kept
+		if leafB || (!leafA && p.a.right-p.a.left >= p.b.right-p.b.left) {
+			for _, child := range splitNode(coordsA, p.a) {
+				stack = append(stack, geoNodePair{child, p.b})
+			}
+		} else {
+			for _, child := range splitNode(coordsB, p.b) {
+				stack = append(stack, geoNodePair{p.a, child})
+			}
+		}
+	}
+}
+
+// splitNode split node into both halves and the middle point between them, bounding box is split by the middle point
+func splitNode[C kdbush.Coord](coords []C, node geoNode) [3]geoNode {
+	mid := (node.left + node.right) >> 1
+	midLng := float64(coords[2*mid])
+	midLat := float64(coords[2*mid+1])
+
+	leftNode := geoNode{left: node.left, right: mid - 1, axis: 1 - node.axis, minLng: node.minLng, minLat: node.minLat, maxLng: node.maxLng, maxLat: node.maxLat}
+	rightNode := geoNode{left: mid + 1, right: node.right, axis: 1 - node.axis, minLng: node.minLng, minLat: node.minLat, maxLng: node.maxLng, maxLat: node.maxLat}
+	if node.axis == 0 {
+		leftNode.maxLng = midLng
+		rightNode.minLng = midLng
+	} else {
+		leftNode.maxLat = midLat
+		rightNode.minLat = midLat
+	}
+
+	return [3]geoNode{leftNode, {left: mid, right: mid, axis: node.axis, minLng: midLng, minLat: midLat, maxLng: midLng, maxLat: midLat}, rightNode}
+}
@@ -0,0 +1,87 @@
+package geo_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/raditzlawliet/kdbush/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test JoinWithin & SelfJoin against brute force, including pairs across the antimeridian
+func TestJoinWithin(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	randomPoints := func(n int) []kdbush.Point {
+		result := []kdbush.Point{}
+		for i := 0; i < n; i++ {
+			lng := rng.Float64()*20 - 10
+			if i%2 == 0 {
+				lng = rng.Float64()*20 + 170
+				if lng > 180 {
+					lng -= 360
+				}
+			}
+			result = append(result, &geo.MarkerPoint{Lng: lng, Lat: rng.Float64()*20 + 60})
+		}
+		return result
+	}
+	pointsA := randomPoints(1_000)
+	pointsB := randomPoints(1_500)
+
+	a := kdbush.NewBush().BuildIndex(pointsA, 8)
+	b := kdbush.NewBush().BuildIndex(pointsB, 8)
+	dist := 30.0
+
+	expected := [][2]int{}
+	expectedSelf := [][2]int{}
+	for i, p := range pointsA {
+		for j, q := range pointsB {
+			if geo.Distance(p.GetX(), p.GetY(), q.GetX(), q.GetY()) <= dist {
+				expected = append(expected, [2]int{i, j})
+			}
+		}
+		for j := i + 1; j < len(pointsA); j++ {
+			if geo.Distance(p.GetX(), p.GetY(), pointsA[j].GetX(), pointsA[j].GetY()) <= dist {
+				expectedSelf = append(expectedSelf, [2]int{i, j})
+			}
+		}
+	}
+
+	pairs := [][2]int{}
+	geo.JoinWithin(a, b, dist, func(ia, ib int, distInKm float64) bool {
+		assert.InDelta(t, distInKm, geo.Distance(pointsA[ia].GetX(), pointsA[ia].GetY(), pointsB[ib].GetX(), pointsB[ib].GetY()), 1e-6)
+		pairs = append(pairs, [2]int{ia, ib})
+		return true
+	})
+	assert.NotEmpty(t, expected)
+	assert.ElementsMatch(t, pairs, expected, "it should be same with brute force")
+
+	pairs = [][2]int{}
+	geo.SelfJoin(a, dist, func(ia, ib int, distInKm float64) bool {
+		if ia > ib {
+			ia, ib = ib, ia
+		}
+		pairs = append(pairs, [2]int{ia, ib})
+		return true
+	})
+	assert.NotEmpty(t, expectedSelf)
+	assert.ElementsMatch(t, pairs, expectedSelf, "each pair should be reported once")
+}
+
+// Test SelfJoin on simple points
+func TestSelfJoin(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, kdbush.STANDARD_NODE_SIZE)
+
+	// Jakarta points are farther apart than 3km, Surabaya points are not
+	pairs := [][2]int{}
+	geo.SelfJoin(bush, 3, func(ia, ib int, distInKm float64) bool {
+		if ia > ib {
+			ia, ib = ib, ia
+		}
+		pairs = append(pairs, [2]int{ia, ib})
+		return true
+	})
+	assert.ElementsMatch(t, pairs, [][2]int{{0, 1}, {0, 2}, {0, 3}, {0, 4}, {0, 5}, {1, 2}, {1, 3}, {1, 4}, {1, 5}, {2, 3}, {2, 4}, {2, 5}, {3, 4}, {3, 5}, {4, 5}})
+}
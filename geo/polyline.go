@@ -0,0 +1,201 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/raditzlawliet/kdbush"
+)
+
+// NearPolyline returns ids of points within distance in kilometers of any great circle segment of [path] of (lng, lat), each point is returned once
+func NearPolyline[C kdbush.Coord, I kdbush.ID](bush *kdbush.KDBushOf[C, I], path [][2]float64, distInKm float64) []I {
+	result := []I{}
+	NearPolylineFunc(bush, path, distInKm, func(id I, segment int, offsetInKm, distInKm float64) bool {
+		result = append(result, id)
+		return true
+	})
+	return result
+}
+
+// NearPolylineFunc calls fn for each point within distance in kilometers of any great circle segment of [path] of (lng, lat), with index of the nearest segment,
+// along-track offset in kilometers of its projection from start of the path, and cross-track distance in kilometers. If fn returns false, NearPolylineFunc stops the traversal
+func NearPolylineFunc[C kdbush.Coord, I kdbush.ID](bush *kdbush.KDBushOf[C, I], path [][2]float64, distInKm float64, fn func(id I, segment int, offsetInKm, distInKm float64) bool) {
+	if len(path) == 0 || distInKm < 0 || !bush.Indexed() {
+		return
+	}
+
+	dist := distInKm / earthRadius
+	segments := newGeoSegments(path, dist)
+
+	ids := bush.GetIndexes()
+	coords := bush.GetCoords()
+
+	// visit point at position i, return false to stop
+	visit := func(i int) bool {
+		if bush.DeletedAt(i) {
+			return true
+		}
+
+		lng := float64(coords[2*i])
+		lat := float64(coords[2*i+1])
+		nearest, offset, d := -1, 0.0, dist
+		for j := range segments {
+			if segOffset, segDist := segments[j].project(lng, lat); segDist <= d {
+				nearest, offset, d = j, segments[j].offset+segOffset, segDist
+			}
+		}
+		if nearest < 0 {
+			return true
+		}
+		return fn(ids[i], nearest, offset*earthRadius, d*earthRadius)
+	}
+
+	// whole Earth on top of the stack
+	stack := []geoNode{{left: 0, right: len(ids) - 1, axis: 0, minLng: -180, minLat: -90, maxLng: 180, maxLat: 90}}
+
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1] // .pop()
+
+		if node.left > node.right {
+			continue
+		}
+
+		// prune node that far from bounding sphere of all segments
+		near := false
+		for j := range segments {
+			if boxDist(segments[j].midLng, segments[j].midLat, segments[j].cosMidLat, &node) <= segments[j].maxHaverSinDist {
+				near = true
+				break
+			}
+		}
+		if !near {
+			continue
+		}
+
+		if node.right-node.left <= bush.GetNodeSize() {
+			// leaf node
+			for i := node.left; i <= node.right; i++ {
+				if !visit(i) {
+					return
+				}
+			}
+			continue
+		}
+
+		mid := (node.left + node.right) >> 1
+		if !visit(mid) {
+			return
+		}
+
+		midLng := float64(coords[2*mid])
+		midLat := float64(coords[2*mid+1])
+
+		leftNode := geoNode{left: node.left, right: mid - 1, axis: 1 - node.axis, minLng: node.minLng, minLat: node.minLat, maxLng: node.maxLng, maxLat: node.maxLat}
+		rightNode := geoNode{left: mid + 1, right: node.right, axis: 1 - node.axis, minLng: node.minLng, minLat: node.minLat, maxLng: node.maxLng, maxLat: node.maxLat}
+		if node.axis == 0 {
+			leftNode.maxLng = midLng
+			rightNode.minLng = midLng
+		} else {
+			leftNode.maxLat = midLat
+			rightNode.minLat = midLat
+		}
+		stack = append(stack, leftNode, rightNode)
+	}
+}
+
+// geoSegment great circle segment of path, all angles are in radians
+type geoSegment struct {
+	lng1, lat1 float64
+	lng2, lat2 float64
+	cosLat1    float64
+	bearing    float64
+	length     float64
+
+	// along-track offset of start of segment from start of the path
+	offset float64
+
+	// midpoint & haversine of radius of bounding sphere, half-length + distance
+	midLng, midLat  float64
+	cosMidLat       float64
+	maxHaverSinDist float64
+}
+
+// newGeoSegments return great circle segments of path, single location path is a zero-length segment
+func newGeoSegments(path [][2]float64, dist float64) []geoSegment {
+	if len(path) == 1 {
+		path = [][2]float64{path[0], path[0]}
+	}
+	segments := make([]geoSegment, len(path)-1)
+
+	offset := 0.0
+	for i := range segments {
+		a := path[i]
+		b := path[i+1]
+
+		s := geoSegment{
+			lng1: a[0], lat1: a[1],
+			lng2: b[0], lat2: b[1],
+			cosLat1: math.Cos(a[1] * rad),
+			offset:  offset,
+		}
+		s.bearing = bearing(s.lng1, s.lat1, s.lng2, s.lat2)
+		s.length = angularDist(s.lng1, s.lat1, s.lng2, s.lat2, s.cosLat1)
+		s.midLng, s.midLat = midpoint(s.lng1, s.lat1, s.lng2, s.lat2)
+		s.cosMidLat = math.Cos(s.midLat * rad)
+		s.maxHaverSinDist = haverSin(math.Min(s.length/2+dist, math.Pi))
+
+		segments[i] = s
+		offset += s.length
+	}
+
+	return segments
+}
+
+// project return along-track offset of location projected into the segment, and angular distance between them
+func (s *geoSegment) project(lng, lat float64) (offset, dist float64) {
+	d13 := angularDist(s.lng1, s.lat1, lng, lat, s.cosLat1)
+	if s.length == 0 {
+		return 0, d13
+	}
+
+	dBearing := bearing(s.lng1, s.lat1, lng, lat) - s.bearing
+	crossTrack := math.Asin(math.Sin(d13) * math.Sin(dBearing))
+	alongTrack := math.Acos(math.Max(-1, math.Min(1, math.Cos(d13)/math.Cos(crossTrack))))
+
+	// behind start of segment
+	if math.Cos(dBearing) < 0 {
+		return 0, d13
+	}
+	// beyond end of segment
+	if alongTrack > s.length {
+		return s.length, angularDist(s.lng2, s.lat2, lng, lat, math.Cos(s.lat2*rad))
+	}
+	return alongTrack, math.Abs(crossTrack)
+}
+
+// angularDist great circle distance between two locations in radians
+func angularDist(lng1, lat1, lng2, lat2, cosLat1 float64) float64 {
+	return 2 * math.Asin(math.Sqrt(math.Min(1, haverSinDist(lng1, lat1, lng2, lat2, cosLat1))))
+}
+
+// bearing initial bearing from first location to second location in radians
+func bearing(lng1, lat1, lng2, lat2 float64) float64 {
+	dLng := (lng2 - lng1) * rad
+	sinLat1, cosLat1 := math.Sincos(lat1 * rad)
+	sinLat2, cosLat2 := math.Sincos(lat2 * rad)
+	return math.Atan2(math.Sin(dLng)*cosLat2, cosLat1*sinLat2-sinLat1*cosLat2*math.Cos(dLng))
+}
+
+// midpoint middle location of great circle segment in degrees, longitude is in [-180, 180]
+func midpoint(lng1, lat1, lng2, lat2 float64) (lng, lat float64) {
+	dLng := (lng2 - lng1) * rad
+	sinLat1, cosLat1 := math.Sincos(lat1 * rad)
+	sinLat2, cosLat2 := math.Sincos(lat2 * rad)
+	bx := cosLat2 * math.Cos(dLng)
+	by := cosLat2 * math.Sin(dLng)
+
+	lat = math.Atan2(sinLat1+sinLat2, math.Hypot(cosLat1+bx, by)) / rad
+	lng = lng1 + math.Atan2(by, cosLat1+bx)/rad
+	lng = math.Mod(lng+540, 360) - 180
+	return lng, lat
+}
@@ -0,0 +1,110 @@
+package geo_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/raditzlawliet/kdbush/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test NearPolyline against distance to densely sampled great circle route
+func TestNearPolyline(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	_points := []kdbush.Point{}
+	for i := 0; i < 5_000; i++ {
+		_points = append(_points, &geo.MarkerPoint{Lng: rng.Float64()*20 + 100, Lat: rng.Float64()*20 - 10})
+	}
+	bush := kdbush.NewBush().BuildIndex(_points, 8)
+
+	// Surabaya - Jakarta - Singapore
+	path := [][2]float64{{112.75, -7.26}, {106.83, -6.17}, {103.82, 1.35}}
+	samples := sampleRoute(path, 1000)
+	dist := 50.0
+
+	found := map[int]float64{}
+	geo.NearPolylineFunc(bush, path, dist, func(id int, segment int, offsetInKm, distInKm float64) bool {
+		_, ok := found[id]
+		assert.False(t, ok, "each point should be returned once")
+		found[id] = distInKm
+		return true
+	})
+
+	for i, p := range _points {
+		bruteDist := math.Inf(1)
+		for _, s := range samples {
+			bruteDist = math.Min(bruteDist, geo.Distance(p.GetX(), p.GetY(), s[0], s[1]))
+		}
+
+		if d, ok := found[i]; ok {
+			assert.InDelta(t, d, bruteDist, 0.5, "cross-track distance should be same with brute force")
+		}
+		// sampling is not exact, skip points near the edge of the corridor
+		if math.Abs(bruteDist-dist) > 0.5 {
+			_, ok := found[i]
+			assert.Equal(t, bruteDist < dist, ok, "it should be same with brute force")
+		}
+	}
+
+	assert.NotEmpty(t, found)
+	assert.ElementsMatch(t, geo.NearPolyline(bush, path, dist), keys(found))
+}
+
+// Test NearPolyline segment & offset
+func TestNearPolylineMatch(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, kdbush.STANDARD_NODE_SIZE)
+
+	// route from Monas to Submarine Monument, pass Tugu Proklamasi
+	path := [][2]float64{{points[7].GetX(), points[7].GetY()}, {points[6].GetX(), points[6].GetY()}, {points[0].GetX(), points[0].GetY()}}
+	geo.NearPolylineFunc(bush, path, 0.1, func(id int, segment int, offsetInKm, distInKm float64) bool {
+		switch id {
+		case 7:
+			assert.Equal(t, segment, 0)
+			assert.InDelta(t, offsetInKm, 0, 1e-6)
+		case 6:
+			assert.InDelta(t, offsetInKm, geo.Distance(path[0][0], path[0][1], path[1][0], path[1][1]), 1e-6)
+		case 0:
+			assert.Equal(t, segment, 1)
+			assert.InDelta(t, offsetInKm, geo.Distance(path[0][0], path[0][1], path[1][0], path[1][1])+geo.Distance(path[1][0], path[1][1], path[2][0], path[2][1]), 1e-6)
+		}
+		assert.InDelta(t, distInKm, 0, 1e-6)
+		return true
+	})
+
+	assert.ElementsMatch(t, geo.NearPolyline(bush, path, 0.1), []int{0, 6, 7})
+	assert.ElementsMatch(t, geo.NearPolyline(bush, path[:1], 5), geo.Around(bush, path[0][0], path[0][1], -1, 5, nil), "single location should be same with Around")
+	assert.Equal(t, geo.NearPolyline(bush, nil, 5), []int{}, "it should be empty without path")
+}
+
+// sampleRoute interpolate n locations along each great circle segment of path
+func sampleRoute(path [][2]float64, n int) [][2]float64 {
+	const rad = math.Pi / 180
+	samples := [][2]float64{}
+	for i := 0; i+1 < len(path); i++ {
+		lng1, lat1 := path[i][0]*rad, path[i][1]*rad
+		lng2, lat2 := path[i+1][0]*rad, path[i+1][1]*rad
+		delta := geo.Distance(path[i][0], path[i][1], path[i+1][0], path[i+1][1]) / 6371
+		for j := 0; j <= n; j++ {
+			f := float64(j) / float64(n)
+			a := math.Sin((1-f)*delta) / math.Sin(delta)
+			b := math.Sin(f*delta) / math.Sin(delta)
+			x := a*math.Cos(lat1)*math.Cos(lng1) + b*math.Cos(lat2)*math.Cos(lng2)
+			y := a*math.Cos(lat1)*math.Sin(lng1) + b*math.Cos(lat2)*math.Sin(lng2)
+			z := a*math.Sin(lat1) + b*math.Sin(lat2)
+			samples = append(samples, [2]float64{math.Atan2(y, x) / rad, math.Atan2(z, math.Hypot(x, y)) / rad})
+		}
+	}
+	return samples
+}
+
+// keys return keys of map
+func keys(m map[int]float64) []int {
+	result := []int{}
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
@@ -2,6 +2,7 @@ package kdbush
 
 import (
 	"math"
+	"sync"
 )
 
 // This helper is for kdbush functionality
@@ -11,22 +12,55 @@ type Number interface {
 	float64 | int
 }
 
-// sort
-func sort(ids []int, coords []float64, nodeSize, left, right, axis int) {
+// sort build kd-tree of points with dim dimension, coords of i-th point are coords[dim*i : dim*i+dim]
+func sort[C Coord, I ID](ids []I, coords []C, nodeSize, left, right, axis, dim int) {
 	if (right - left) <= nodeSize {
 		return
 	}
 
 	m := (left + right) >> 1
 
-	selection(ids, coords, m, left, right, axis)
+	selection(ids, coords, m, left, right, axis, dim)
 
-	sort(ids, coords, nodeSize, left, m-1, 1-axis)
-	sort(ids, coords, nodeSize, m+1, right, 1-axis)
+	sort(ids, coords, nodeSize, left, m-1, (axis+1)%dim, dim)
+	sort(ids, coords, nodeSize, m+1, right, (axis+1)%dim, dim)
+}
+
+// parallelSortThreshold minimum size of subtree to be sorted in another goroutine, smaller one is cheaper to sort serially
+const parallelSortThreshold = 1 << 13
+
+// sortParallel same as sort, but spread independent subtrees across goroutines while token in sem is available.
+// Result is identical with sort since each subtree is sorted exactly the same way
+func sortParallel[C Coord, I ID](ids []I, coords []C, nodeSize, left, right, axis, dim int, sem chan struct{}, wg *sync.WaitGroup) {
+	if (right - left) <= nodeSize {
+		return
+	}
+
+	m := (left + right) >> 1
+
+	selection(ids, coords, m, left, right, axis, dim)
+
+	if (right - left) >= parallelSortThreshold {
+		select {
+		case sem <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sortParallel(ids, coords, nodeSize, left, m-1, (axis+1)%dim, dim, sem, wg)
+				<-sem
+			}()
+			sortParallel(ids, coords, nodeSize, m+1, right, (axis+1)%dim, dim, sem, wg)
+			return
+		default:
+		}
+	}
+
+	sortParallel(ids, coords, nodeSize, left, m-1, (axis+1)%dim, dim, sem, wg)
+	sortParallel(ids, coords, nodeSize, m+1, right, (axis+1)%dim, dim, sem, wg)
 }
 
 // selection
-func selection(ids []int, coords []float64, k, left, right, axis int) {
+func selection[C Coord, I ID](ids []I, coords []C, k, left, right, axis, dim int) {
 	for right > left {
 		if (right - left) > 600 {
 			n := float64(right - left + 1)
@@ -44,36 +78,36 @@ func selection(ids []int, coords []float64, k, left, right, axis int) {
 			newLeft := max(left, int(math.Floor(float64(k)-m*s/n+sd)))
 			newRight := min(right, int(math.Floor(float64(k)+(n-m)*s/n+sd)))
 
-			selection(ids, coords, k, newLeft, newRight, axis)
+			selection(ids, coords, k, newLeft, newRight, axis, dim)
 		}
 
-		t := coords[2*k+axis]
+		t := coords[dim*k+axis]
 		i := left
 		j := right
 
-		swapItem(ids, coords, left, k)
-		if coords[2*right+axis] > t {
-			swapItem(ids, coords, left, right)
+		swapItem(ids, coords, dim, left, k)
+		if coords[dim*right+axis] > t {
+			swapItem(ids, coords, dim, left, right)
 		}
 
 		for i < j {
-			swapItem(ids, coords, i, j)
+			swapItem(ids, coords, dim, i, j)
 			i += 1
 			j -= 1
 
-			for coords[2*i+axis] < t {
+			for coords[dim*i+axis] < t {
 				i += 1
 			}
-			for coords[2*j+axis] > t {
+			for coords[dim*j+axis] > t {
 				j -= 1
 			}
 		}
 
-		if coords[2*left+axis] == t {
-			swapItem(ids, coords, left, j)
+		if coords[dim*left+axis] == t {
+			swapItem(ids, coords, dim, left, j)
 		} else {
 			j += 1
-			swapItem(ids, coords, j, right)
+			swapItem(ids, coords, dim, j, right)
 		}
 
 		if j <= k {
@@ -86,14 +120,15 @@ func selection(ids []int, coords []float64, k, left, right, axis int) {
 }
 
 // swapItem
-func swapItem(ids []int, coords []float64, i, j int) {
+func swapItem[C Coord, I ID](ids []I, coords []C, dim, i, j int) {
 	swap(ids, i, j)
-	swap(coords, i*2, j*2)
-	swap(coords, i*2+1, j*2+1)
+	for a := 0; a < dim; a++ {
+		swap(coords, i*dim+a, j*dim+a)
+	}
 }
 
 // swap
-func swap[T Number](arr []T, i, j int) {
+func swap[T any](arr []T, i, j int) {
 	v := arr[i]
 	arr[i] = arr[j]
 	arr[j] = v
@@ -121,3 +156,10 @@ func sqrtDist(ax, ay, bx, by float64) float64 {
 	dy := ay - by
 	return dx*dx + dy*dy
 }
+
+// boxDist calculate squared distance from a point to bounding box, 0 if the point is inside
+func boxDist(x, y, minX, minY, maxX, maxY float64) float64 {
+	dx := max(minX-x, max(0, x-maxX))
+	dy := max(minY-y, max(0, y-maxY))
+	return dx*dx + dy*dy
+}
@@ -0,0 +1,80 @@
+package kdbush_test
+
+import (
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test BuildIndexWithIDs return user supplied ids on queries
+func TestBuildIndexWithIDs(t *testing.T) {
+	// primary keys of each point
+	keys := make([]uint64, len(points))
+	for i := range points {
+		keys[i] = uint64(1_000_000_000_000 + 7*i)
+	}
+	key := func(x, y float64) uint64 {
+		return keys[indexOf(x, y)]
+	}
+
+	bush := kdbush.NewBushOf[float64, uint64]().BuildIndexWithIDs(points, keys, 10)
+	positional := kdbush.NewBush().BuildIndex(points, 10)
+
+	toKeys := func(indexes []int) []uint64 {
+		result := []uint64{}
+		for _, i := range indexes {
+			result = append(result, keys[i])
+		}
+		return result
+	}
+
+	assert.ElementsMatch(t, bush.Range(-2.1, 1.0, 2.1, 1.0), []uint64{key(-2, 1), key(-1, 1), key(0, 1), key(1, 1), key(2, 1)})
+	assert.ElementsMatch(t, bush.Within(3, 3, 2), toKeys(positional.Within(3, 3, 2)))
+	assert.Equal(t, bush.Nearest(3, 3, 5, -1, nil), toKeys(positional.Nearest(3, 3, 5, -1, nil)))
+
+	// tombstone & builder work with keys
+	assert.True(t, bush.Delete(key(0, 1)))
+	assert.False(t, bush.Delete(0), "position is not an id")
+	assert.NotContains(t, bush.Range(-2.1, 1.0, 2.1, 1.0), key(0, 1))
+
+	id := bush.Add(0.5, 1)
+	assert.Equal(t, id, int(keys[len(keys)-1])+1, "it should continue after the last id")
+	bush.Finish()
+	assert.Contains(t, bush.Range(-2.1, 1.0, 2.1, 1.0), uint64(id))
+	assert.NotContains(t, bush.Range(-2.1, 1.0, 2.1, 1.0), key(0, 1))
+
+	assert.Panics(t, func() {
+		kdbush.NewBush().BuildIndexWithIDs(points, []int{1, 2}, 10)
+	}, "it should panic on length mismatch")
+
+	// ids that can't fit in int can't be continued by Add
+	large := kdbush.NewBushOf[float64, uint64]().BuildIndexWithIDs(points[:2], []uint64{1, 1 << 63}, 10)
+	assert.PanicsWithValue(t, "kdbush: id is too large to continue with Add", func() { large.Add(0, 0) })
+	assert.Equal(t, len(large.Range(-10, -10, 10, 10)), 2, "it should not modify the index")
+
+	full := kdbush.NewBushOf[float64, uint16]().BuildIndexWithIDs(points[:1], []uint16{65535}, 10)
+	assert.PanicsWithValue(t, "kdbush: id of Add can't fit in the index type", func() { full.Add(0, 0) })
+}
+
+// Test MarshalBinary with user supplied ids
+func TestMarshalBinaryWithIDs(t *testing.T) {
+	ids := make([]uint32, len(points))
+	for i := range points {
+		ids[i] = uint32(len(points) - i)
+	}
+	bush := kdbush.NewBushOf[float64, uint32]().BuildIndexWithIDs(points, ids, 10)
+
+	data, err := bush.MarshalBinary()
+	assert.Nil(t, err)
+	loaded := kdbush.NewBushOf[float64, uint32]()
+	assert.Nil(t, loaded.UnmarshalBinary(data))
+	assert.Equal(t, loaded.Range(-2.1, 1.0, 2.1, 1.0), bush.Range(-2.1, 1.0, 2.1, 1.0), "ids should be kept")
+
+	ids[0] = 1 << 16
+	_, err = bush.BuildIndexWithIDs(points, ids, 10).MarshalBinary()
+	assert.Equal(t, err, kdbush.ErrIDOutOfRange, "id should fit in uint16 for less than 65536 items")
+
+	_, err = kdbush.NewBush().BuildIndexWithIDs(points[:1], []int{-1}, 10).MarshalBinary()
+	assert.Equal(t, err, kdbush.ErrIDOutOfRange, "negative id can't be encoded")
+}
@@ -0,0 +1,33 @@
+//go:build go1.23
+
+package kdbush
+
+import "iter"
+
+// RangeSeq returns iterator of indexes points across [minX], [minY], [maxX], [maxY].
+// Points are searched lazily, stop the iteration to stop the traversal
+func (kd *KDBushOf[C, I]) RangeSeq(minX, minY, maxX, maxY float64) iter.Seq[I] {
+	return func(yield func(I) bool) {
+		kd.RangeFunc(minX, minY, maxX, maxY, func(id I, x, y float64) bool {
+			return yield(id)
+		})
+	}
+}
+
+// WithinSeq returns iterator of indexes and squared distance of points within radius of given single [Point].
+// Points are searched lazily, stop the iteration to stop the traversal
+func (kd *KDBushOf[C, I]) WithinSeq(qx, qy float64, radius float64) iter.Seq2[I, float64] {
+	return func(yield func(I, float64) bool) {
+		kd.WithinFunc(qx, qy, radius, func(id I, x, y float64) bool {
+			return yield(id, sqrtDist(x, y, qx, qy))
+		})
+	}
+}
+
+// NearestSeq returns iterator of indexes and squared distance of points closest from given single [Point] in order of increasing distance.
+// Points are searched lazily, stop the iteration to stop the search
+func (kd *KDBushOf[C, I]) NearestSeq(qx, qy float64, maxDist float64, filter func(I) bool) iter.Seq2[I, float64] {
+	return func(yield func(I, float64) bool) {
+		kd.NearestFunc(qx, qy, maxDist, filter, yield)
+	}
+}
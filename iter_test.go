@@ -0,0 +1,68 @@
+//go:build go1.23
+
+package kdbush_test
+
+import (
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test RangeSeq give same result with Range
+func TestRangeSeq(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, 4)
+
+	result := []int{}
+	for id := range bush.RangeSeq(-2.1, 1, 2.1, 2) {
+		result = append(result, id)
+	}
+	assert.Equal(t, result, bush.Range(-2.1, 1, 2.1, 2))
+
+	// stop early
+	count := 0
+	for range bush.RangeSeq(-10, -10, 10, 10) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	assert.Equal(t, count, 3)
+
+	for range kdbush.NewBush().RangeSeq(-10, -10, 10, 10) {
+		t.Error("it should be empty")
+	}
+}
+
+// Test WithinSeq give same result with Within
+func TestWithinSeq(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, 4)
+
+	result := []int{}
+	for id, dist := range bush.WithinSeq(0.3, 0.2, 2) {
+		dx := points[id].GetX() - 0.3
+		dy := points[id].GetY() - 0.2
+		assert.InDelta(t, dist, dx*dx+dy*dy, 1e-9, "it should be squared distance")
+		result = append(result, id)
+	}
+	assert.Equal(t, result, bush.Within(0.3, 0.2, 2))
+
+	for id := range bush.WithinSeq(0, 0, 5) {
+		assert.Equal(t, bush.Within(0, 0, 5)[0], id, "it should be same first element")
+		break
+	}
+}
+
+// Test NearestSeq give same result with Nearest
+func TestNearestSeq(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, 4)
+
+	result := []int{}
+	for id := range bush.NearestSeq(0.1, 0.2, -1, nil) {
+		result = append(result, id)
+		if len(result) == 7 {
+			break
+		}
+	}
+	assert.Equal(t, result, bush.Nearest(0.1, 0.2, 7, -1, nil))
+}
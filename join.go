@@ -0,0 +1,119 @@
+package kdbush
+
+// JoinWithin calls fn for each pair of point in a and point in b within [dist] of each other, with their squared distance.
+// Both kd-trees are traversed together, so node pairs farther apart than dist are pruned at once. If fn returns false, JoinWithin stops the traversal
+func JoinWithin[C1 Coord, I1 ID, C2 Coord, I2 ID](a *KDBushOf[C1, I1], b *KDBushOf[C2, I2], dist float64, fn func(ia I1, ib I2, d2 float64) bool) {
+	join(a, b, dist, false, fn)
+}
+
+// SelfJoin calls fn for each pair of points in kd within [dist] of each other, with their squared distance.
+// Each pair is reported once and a point is never paired with itself. If fn returns false, SelfJoin stops the traversal
+func SelfJoin[C Coord, I ID](kd *KDBushOf[C, I], dist float64, fn func(ia, ib I, d2 float64) bool) {
+	join(kd, kd, dist, true, fn)
+}
+
+// join traverse both kd-trees together and calls fn for each pair of points within dist.
+// If [self], a and b are the same index, then each pair is visited once by pairing same node only with itself and the following nodes
+func join[C1 Coord, I1 ID, C2 Coord, I2 ID](a *KDBushOf[C1, I1], b *KDBushOf[C2, I2], dist float64, self bool, fn func(ia I1, ib I2, d2 float64) bool) {
+	if !a.indexed || !b.indexed || dist < 0 {
+		return
+	}
+
+	r2 := dist * dist
+	stack := []joinPair{{a.root(), b.root()}}
+
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1] // .pop()
+
+		if p.a.left > p.a.right || p.b.left > p.b.right || boxBoxDist(p.a, p.b) > r2 {
+			continue
+		}
+
+		// on self join, other pairs than same node are always disjoint
+		same := self && p.a.left == p.b.left && p.a.right == p.b.right
+
+		leafA := a.isLeaf(p.a)
+		leafB := b.isLeaf(p.b)
+		if leafA && leafB {
+			for i := p.a.left; i <= p.a.right; i++ {
+				if a.DeletedAt(i) {
+					continue
+				}
+				ax := float64(a.coords[2*i])
+				ay := float64(a.coords[2*i+1])
+
+				j := p.b.left
+				if same {
+					j = i + 1
+				}
+				for ; j <= p.b.right; j++ {
+					d2 := sqrtDist(ax, ay, float64(b.coords[2*j]), float64(b.coords[2*j+1]))
+					if d2 <= r2 && !b.DeletedAt(j) && !fn(a.ids[i], b.ids[j], d2) {
+						return
+					}
+				}
+			}
+			continue
+		}
+
+		if same {
+			// pair each child with itself and the following children
+			children := a.split(p.a)
+			for i := range children {
+				for j := i; j < len(children); j++ {
+					stack = append(stack, joinPair{children[i], children[j]})
+				}
+			}
+			continue
+		}
+
+		// split the bigger node, the other one is kept
+		if leafB || (!leafA && p.a.right-p.a.left >= p.b.right-p.b.left) {
+			for _, child := range a.split(p.a) {
+				stack = append(stack, joinPair{child, p.b})
+			}
+		} else {
+			for _, child := range b.split(p.b) {
+				stack = append(stack, joinPair{p.a, child})
+			}
+		}
+	}
+}
+
+// joinPair pair of nodes from both kd-trees for JoinWithin & SelfJoin
+type joinPair struct {
+	a boxQuery
+	b boxQuery
+}
+
+// isLeaf return node is small enough to be searched linearly
+func (kd *KDBushOf[C, I]) isLeaf(n boxQuery) bool {
+	return n.right-n.left <= kd.nodeSize
+}
+
+// split node into both halves and the middle point between them, bounding box is split by the middle point
+func (kd *KDBushOf[C, I]) split(n boxQuery) [3]boxQuery {
+	m := (n.left + n.right) >> 1
+	x := float64(kd.coords[2*m])
+	y := float64(kd.coords[2*m+1])
+
+	leftNode := boxQuery{n.left, m - 1, 1 - n.axis, n.minX, n.minY, n.maxX, n.maxY}
+	rightNode := boxQuery{m + 1, n.right, 1 - n.axis, n.minX, n.minY, n.maxX, n.maxY}
+	if n.axis == 0 {
+		leftNode.maxX = x
+		rightNode.minX = x
+	} else {
+		leftNode.maxY = y
+		rightNode.minY = y
+	}
+
+	return [3]boxQuery{leftNode, {m, m, n.axis, x, y, x, y}, rightNode}
+}
+
+// boxBoxDist calculate squared distance between two bounding boxes, 0 if they intersect
+func boxBoxDist(a, b boxQuery) float64 {
+	dx := max(a.minX-b.maxX, max(0, b.minX-a.maxX))
+	dy := max(a.minY-b.maxY, max(0, b.minY-a.maxY))
+	return dx*dx + dy*dy
+}
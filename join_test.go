@@ -0,0 +1,97 @@
+package kdbush_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test JoinWithin against Within of each point
+func TestJoinWithin(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	pointsA := []kdbush.Point{}
+	for i := 0; i < 2_000; i++ {
+		pointsA = append(pointsA, &kdbush.SimplePoint{rng.Float64() * 100, rng.Float64() * 100})
+	}
+	pointsB := []kdbush.Point{}
+	for i := 0; i < 3_000; i++ {
+		pointsB = append(pointsB, &kdbush.SimplePoint{rng.Float64()*100 + 20, rng.Float64() * 100})
+	}
+
+	for _, nodeSize := range []int{kdbush.STANDARD_NODE_SIZE, 4} {
+		a := kdbush.NewBush().BuildIndex(pointsA, nodeSize)
+		b := kdbush.NewBush().BuildIndex(pointsB, nodeSize)
+		b.Delete(0)
+
+		for _, dist := range []float64{0.5, 2} {
+			expected := [][2]int{}
+			for i, p := range pointsA {
+				for _, j := range b.Within(p.GetX(), p.GetY(), dist) {
+					expected = append(expected, [2]int{i, j})
+				}
+			}
+
+			pairs := [][2]int{}
+			kdbush.JoinWithin(a, b, dist, func(ia, ib int, d2 float64) bool {
+				assert.InDelta(t, d2, sqDist(pointsA[ia], pointsB[ib].GetX(), pointsB[ib].GetY()), 1e-9, "it should report squared distance")
+				pairs = append(pairs, [2]int{ia, ib})
+				return true
+			})
+			assert.Equal(t, countPairs(pairs), countPairs(expected), "it should be same with Within of each point")
+		}
+	}
+
+	count := 0
+	kdbush.JoinWithin(kdbush.NewBush().BuildIndex(points, 10), kdbush.NewBush().BuildIndex(points, 10), 1, func(ia, ib int, d2 float64) bool {
+		count++
+		return count < 5
+	})
+	assert.Equal(t, count, 5, "it should stop when fn returns false")
+}
+
+// Test SelfJoin against Within of each point
+func TestSelfJoin(t *testing.T) {
+	for _, nodeSize := range []int{kdbush.STANDARD_NODE_SIZE, 4, 1} {
+		bush := kdbush.NewBush().BuildIndex(points, nodeSize)
+		bush.Delete(indexOf(0, 0))
+
+		expected := [][2]int{}
+		for i, p := range points {
+			if i == indexOf(0, 0) {
+				continue
+			}
+			for _, j := range bush.Within(p.GetX(), p.GetY(), 1.5) {
+				if i < j {
+					expected = append(expected, [2]int{i, j})
+				}
+			}
+		}
+
+		pairs := [][2]int{}
+		kdbush.SelfJoin(bush, 1.5, func(ia, ib int, d2 float64) bool {
+			if ia > ib {
+				ia, ib = ib, ia
+			}
+			pairs = append(pairs, [2]int{ia, ib})
+			return true
+		})
+		assert.ElementsMatch(t, pairs, expected, "each pair should be reported once")
+	}
+
+	kdbush.SelfJoin(kdbush.NewBush().BuildIndex([]kdbush.Point{}, 4), 1, func(ia, ib int, d2 float64) bool {
+		assert.Fail(t, "it should be empty")
+		return true
+	})
+}
+
+// countPairs return number of occurrence of each pair, cheaper than ElementsMatch on many pairs
+func countPairs(pairs [][2]int) map[[2]int]int {
+	result := map[[2]int]int{}
+	for _, p := range pairs {
+		result[p]++
+	}
+	return result
+}
@@ -1,41 +1,185 @@
 // Package kdbush implements kdbush-tree
 package kdbush
 
+import "math"
+
 // STANDARD_NODE_SIZE default nodeSize kdbush-tree. Higher value means faster indexing but slower search and vice versa
 const STANDARD_NODE_SIZE = 64
 
-// KDBush an instance
-type KDBush struct {
+// Coord numeric types that can be used to store coordinates, same with typed arrays supported by Javascript KDBush
+type Coord interface {
+	int8 | uint8 | int16 | uint16 | int32 | uint32 | float32 | float64
+}
+
+// ID numeric types that can be used to store indexes
+type ID interface {
+	uint16 | uint32 | int32 | int | uint | int64 | uint64
+}
+
+// KDBushOf an instance with coordinates stored as C and indexes stored as I.
+// Smaller types means lower memory footprint, e.g. float32 coords with uint32 ids use half memory of [KDBush]
+type KDBushOf[C Coord, I ID] struct {
 	nodeSize int
-	ids      []int
-	coords   []float64
+	ids      []I
+	coords   []C
 	indexed  bool
+
+	// tombstones of deleted points by position, see [KDBushOf.Delete]
+	deleted      []uint64
+	deletedCount int
+	compactRatio float64
+
+	// position of each id, built on first delete. Dense ids (e.g. [KDBushOf.BuildIndex]) use inverse indexed by id, -1 if not exist,
+	// others (e.g. [KDBushOf.BuildIndexWithIDs]) use positions
+	inverse   []int
+	positions map[I]int
+
+	// next id of [KDBushOf.Add], -1 if unknown
+	nextID int
+
+	// memory-mapped file of ids and/or coords, see [OpenOf]
+	mapping []byte
+
+	// bounding box [minX, minY, maxX, maxY] of all points, infinite if unknown
+	bounds [4]float64
 }
 
+// KDBush an instance with float64 coordinates and int indexes
+type KDBush = KDBushOf[float64, int]
+
 // NewBush return a new pointer of [KDBush]
 func NewBush() *KDBush {
-	kd := KDBush{}
+	return NewBushOf[float64, int]()
+}
+
+// NewBushOf return a new pointer of [KDBushOf] with given coordinate & index type
+func NewBushOf[C Coord, I ID]() *KDBushOf[C, I] {
+	kd := KDBushOf[C, I]{
+		compactRatio: STANDARD_COMPACT_RATIO,
+	}
 	return &kd
 }
 
-// BuildIndex build kd-tree index given list of Points
-func (kd *KDBush) BuildIndex(points []Point, nodeSize int) *KDBush {
-	kd.indexed = false
-	kd.nodeSize = nodeSize
+// BuildIndex build kd-tree index given list of Points.
+// Coordinates are converted into C, e.g. truncated when C is integer type.
+// It panics when indexes of points can't fit in I, use [KDBushOf.TryBuildIndex] to get [ErrTooManyPoints] instead
+func (kd *KDBushOf[C, I]) BuildIndex(points []Point, nodeSize int) *KDBushOf[C, I] {
+	mustFitIDs[I](len(points))
+
+	kd.reset(nodeSize)
 
-	kd.ids = make([]int, len(points))
-	kd.coords = make([]float64, 2*len(points))
+	kd.ids = make([]I, len(points))
+	kd.nextID = len(kd.ids)
+	kd.coords = make([]C, 2*len(points))
 
 	for i, v := range points {
-		kd.ids[i] = i
-		kd.coords[i*2] = v.GetX()
-		kd.coords[i*2+1] = v.GetY()
+		kd.ids[i] = I(i)
+		kd.coords[i*2] = C(v.GetX())
+		kd.coords[i*2+1] = C(v.GetY())
 	}
 
-	sort(kd.ids, kd.coords, kd.nodeSize, 0, len(kd.ids)-1, 0)
+	return kd.Finish()
+}
+
+// BuildIndexParallel same as [KDBushOf.BuildIndex], but sort subtrees using up to [workers] goroutines.
+// Use 0 or less on [workers] for GOMAXPROCS. The index is identical with [KDBushOf.BuildIndex]
+func (kd *KDBushOf[C, I]) BuildIndexParallel(points []Point, nodeSize, workers int) *KDBushOf[C, I] {
+	mustFitIDs[I](len(points))
 
-	kd.indexed = true
-	return kd
+	kd.reset(nodeSize)
+
+	kd.ids = make([]I, len(points))
+	kd.nextID = len(kd.ids)
+	kd.coords = make([]C, 2*len(points))
+
+	for i, v := range points {
+		kd.ids[i] = I(i)
+		kd.coords[i*2] = C(v.GetX())
+		kd.coords[i*2+1] = C(v.GetY())
+	}
+
+	return kd.FinishParallel(workers)
+}
+
+// BuildIndexFromCoords build kd-tree index given flat coordinates [x0, y0, x1, y1, ...].
+// coords is copied, use [KDBushOf.BuildIndexFromCoordsInPlace] to avoid copy
+func (kd *KDBushOf[C, I]) BuildIndexFromCoords(coords []C, nodeSize int) *KDBushOf[C, I] {
+	if len(coords)%2 != 0 {
+		panic("kdbush: coords length must be even")
+	}
+	return kd.BuildIndexFromCoordsInPlace(append([]C(nil), coords...), nodeSize)
+}
+
+// BuildIndexFromCoordsInPlace same as [KDBushOf.BuildIndexFromCoords], but take ownership of coords instead of copy it.
+// coords will be reordered during indexing and must not be modified after
+func (kd *KDBushOf[C, I]) BuildIndexFromCoordsInPlace(coords []C, nodeSize int) *KDBushOf[C, I] {
+	if len(coords)%2 != 0 {
+		panic("kdbush: coords length must be even")
+	}
+	mustFitIDs[I](len(coords) / 2)
+
+	kd.reset(nodeSize)
+
+	kd.ids = make([]I, len(coords)/2)
+	kd.nextID = len(kd.ids)
+	kd.coords = coords
+
+	for i := range kd.ids {
+		kd.ids[i] = I(i)
+	}
+
+	return kd.Finish()
+}
+
+// BuildIndexFromXY build kd-tree index given columnar coordinates xs & ys with same length
+func (kd *KDBushOf[C, I]) BuildIndexFromXY(xs, ys []C, nodeSize int) *KDBushOf[C, I] {
+	if len(xs) != len(ys) {
+		panic("kdbush: xs and ys length must be same")
+	}
+	mustFitIDs[I](len(xs))
+
+	kd.reset(nodeSize)
+
+	kd.ids = make([]I, len(xs))
+	kd.nextID = len(kd.ids)
+	kd.coords = make([]C, 2*len(xs))
+
+	for i := range xs {
+		kd.ids[i] = I(i)
+		kd.coords[i*2] = xs[i]
+		kd.coords[i*2+1] = ys[i]
+	}
+
+	return kd.Finish()
+}
+
+// BuildIndexWithIDs same as [KDBushOf.BuildIndex], but query results return ids[i] for points[i] instead of i, e.g. primary keys of database.
+// ids should be unique and have same length with points
+func (kd *KDBushOf[C, I]) BuildIndexWithIDs(points []Point, ids []I, nodeSize int) *KDBushOf[C, I] {
+	if len(points) != len(ids) {
+		panic("kdbush: points and ids length must be same")
+	}
+
+	kd.reset(nodeSize)
+
+	kd.ids = make([]I, len(points))
+	kd.nextID = -1 // unknown until needed, see [KDBushOf.Add]
+	kd.coords = make([]C, 2*len(points))
+
+	for i, v := range points {
+		kd.ids[i] = ids[i]
+		kd.coords[i*2] = C(v.GetX())
+		kd.coords[i*2+1] = C(v.GetY())
+	}
+
+	return kd.Finish()
+}
+
+// mustFitIDs panic if indexes of n points can't fit in index type I, e.g. more than 65536 points for uint16
+func mustFitIDs[I ID](n int) {
+	if n > 0 && !fitsID[I](uint64(n-1)) {
+		panic("kdbush: number of points can't fit in the index type")
+	}
 }
 
 // query helper struct for API Range & Within finding result
@@ -45,14 +189,33 @@ type query struct {
 	axis  int
 }
 
+// queryStackSize initial size of query stack, enough for kd-tree depth of any practical index, so query stack can stay off the heap
+const queryStackSize = 64
+
 // Range returns all indexes points across [minX], [minY], [maxX], [maxY]
-func (kd *KDBush) Range(minX, minY, maxX, maxY float64) []int {
+func (kd *KDBushOf[C, I]) Range(minX, minY, maxX, maxY float64) []I {
+	return kd.RangeAppend([]I{}, minX, minY, maxX, maxY)
+}
+
+// RangeAppend same as [KDBushOf.Range], but append indexes into dst and return the extended slice.
+// Reuse dst across queries to avoid allocation
+func (kd *KDBushOf[C, I]) RangeAppend(dst []I, minX, minY, maxX, maxY float64) []I {
+	kd.RangeFunc(minX, minY, maxX, maxY, func(id I, x, y float64) bool {
+		dst = append(dst, id)
+		return true
+	})
+	return dst
+}
+
+// RangeFunc calls fn for each point across [minX], [minY], [maxX], [maxY] without allocation.
+// If fn returns false, RangeFunc stops the traversal
+func (kd *KDBushOf[C, I]) RangeFunc(minX, minY, maxX, maxY float64, fn func(id I, x, y float64) bool) {
 	if !kd.indexed {
-		return []int{}
+		return
 	}
 
-	stack := []query{{0, len(kd.ids) - 1, 0}}
-	result := []int{}
+	var buf [queryStackSize]query
+	stack := append(buf[:0], query{0, len(kd.ids) - 1, 0})
 
 	var x, y float64
 
@@ -60,15 +223,17 @@ func (kd *KDBush) Range(minX, minY, maxX, maxY float64) []int {
 		left := stack[len(stack)-1].left
 		right := stack[len(stack)-1].right
 		axis := stack[len(stack)-1].axis
-		stack = append(stack[:len(stack)-1], stack[len(stack):]...) // .pop()
+		stack = stack[:len(stack)-1] // .pop()
 
 		// search linearly
 		if right-left <= kd.nodeSize {
 			for i := left; i <= right; i++ {
-				x = kd.coords[2*i]
-				y = kd.coords[2*i+1]
-				if x >= minX && x <= maxX && y >= minY && y <= maxY {
-					result = append(result, kd.ids[i])
+				x = float64(kd.coords[2*i])
+				y = float64(kd.coords[2*i+1])
+				if x >= minX && x <= maxX && y >= minY && y <= maxY && !kd.DeletedAt(i) {
+					if !fn(kd.ids[i], x, y) {
+						return
+					}
 				}
 			}
 			continue
@@ -78,10 +243,12 @@ func (kd *KDBush) Range(minX, minY, maxX, maxY float64) []int {
 		m := (left + right) >> 1
 
 		// include middle item within range
-		x = kd.coords[2*m]
-		y = kd.coords[2*m+1]
-		if x >= minX && x <= maxX && y >= minY && y <= maxY {
-			result = append(result, kd.ids[m])
+		x = float64(kd.coords[2*m])
+		y = float64(kd.coords[2*m+1])
+		if x >= minX && x <= maxX && y >= minY && y <= maxY && !kd.DeletedAt(m) {
+			if !fn(kd.ids[m], x, y) {
+				return
+			}
 		}
 
 		// queue search in halves that intersect the query
@@ -92,18 +259,32 @@ func (kd *KDBush) Range(minX, minY, maxX, maxY float64) []int {
 			stack = append(stack, query{m + 1, right, 1 - axis})
 		}
 	}
-
-	return result
 }
 
 // Within returns all indexes points within radius of given single [Point]
-func (kd *KDBush) Within(qx, qy float64, radius float64) []int {
+func (kd *KDBushOf[C, I]) Within(qx, qy float64, radius float64) []I {
+	return kd.WithinAppend([]I{}, qx, qy, radius)
+}
+
+// WithinAppend same as [KDBushOf.Within], but append indexes into dst and return the extended slice.
+// Reuse dst across queries to avoid allocation
+func (kd *KDBushOf[C, I]) WithinAppend(dst []I, qx, qy float64, radius float64) []I {
+	kd.WithinFunc(qx, qy, radius, func(id I, x, y float64) bool {
+		dst = append(dst, id)
+		return true
+	})
+	return dst
+}
+
+// WithinFunc calls fn for each point within radius of given single [Point] without allocation.
+// If fn returns false, WithinFunc stops the traversal
+func (kd *KDBushOf[C, I]) WithinFunc(qx, qy float64, radius float64, fn func(id I, x, y float64) bool) {
 	if !kd.indexed {
-		return []int{}
+		return
 	}
 
-	stack := []query{{0, len(kd.ids) - 1, 0}}
-	result := []int{}
+	var buf [queryStackSize]query
+	stack := append(buf[:0], query{0, len(kd.ids) - 1, 0})
 
 	r2 := radius * radius
 
@@ -113,13 +294,17 @@ func (kd *KDBush) Within(qx, qy float64, radius float64) []int {
 		left := stack[len(stack)-1].left
 		right := stack[len(stack)-1].right
 		axis := stack[len(stack)-1].axis
-		stack = append(stack[:len(stack)-1], stack[len(stack):]...) // .pop()
+		stack = stack[:len(stack)-1] // .pop()
 
 		// search linearly
 		if right-left <= kd.nodeSize {
 			for i := left; i <= right; i++ {
-				if sqrtDist(kd.coords[2*i], kd.coords[2*i+1], qx, qy) <= r2 {
-					result = append(result, kd.ids[i])
+				x = float64(kd.coords[2*i])
+				y = float64(kd.coords[2*i+1])
+				if sqrtDist(x, y, qx, qy) <= r2 && !kd.DeletedAt(i) {
+					if !fn(kd.ids[i], x, y) {
+						return
+					}
 				}
 			}
 			continue
@@ -129,10 +314,12 @@ func (kd *KDBush) Within(qx, qy float64, radius float64) []int {
 		m := (left + right) >> 1
 
 		// include the middle item within range
-		x = kd.coords[2*m]
-		y = kd.coords[2*m+1]
-		if sqrtDist(x, y, qx, qy) <= r2 {
-			result = append(result, kd.ids[m])
+		x = float64(kd.coords[2*m])
+		y = float64(kd.coords[2*m+1])
+		if sqrtDist(x, y, qx, qy) <= r2 && !kd.DeletedAt(m) {
+			if !fn(kd.ids[m], x, y) {
+				return
+			}
 		}
 
 		// queue search in halves that intersect the query
@@ -143,8 +330,141 @@ func (kd *KDBush) Within(qx, qy float64, radius float64) []int {
 			stack = append(stack, query{m + 1, right, 1 - axis})
 		}
 	}
+}
+
+// Position of node bounding box against query shape
+const (
+	boxOutside = iota
+	boxInside
+	boxPartial
+)
+
+// classifyRange return position of bounding box [bMinX], [bMinY], [bMaxX], [bMaxY] against range query
+func classifyRange(minX, minY, maxX, maxY, bMinX, bMinY, bMaxX, bMaxY float64) int {
+	if bMinX > maxX || bMaxX < minX || bMinY > maxY || bMaxY < minY {
+		return boxOutside
+	}
+	if bMinX >= minX && bMaxX <= maxX && bMinY >= minY && bMaxY <= maxY {
+		return boxInside
+	}
+	return boxPartial
+}
+
+// boxQuery helper struct for walking kd-tree with bounding box of node
+type boxQuery struct {
+	left  int
+	right int
+	axis  int
+	minX  float64
+	minY  float64
+	maxX  float64
+	maxY  float64
+}
+
+// walk traverse kd-tree and calls fn with position & coordinates of each point inside query shape.
+// Nodes are pruned or accepted entirely based on [classify] of its bounding box, otherwise points are tested with [contains].
+// If [inside] is not nil, it is called with position range of accepted node instead of calling fn for each point.
+// If fn or inside returns false, walk stops the traversal
+func (kd *KDBushOf[C, I]) walk(classify func(minX, minY, maxX, maxY float64) int, contains func(x, y float64) bool, fn func(i int, x, y float64) bool, inside func(left, right int) bool) {
+	if !kd.indexed {
+		return
+	}
+
+	var buf [queryStackSize]boxQuery
+	stack := append(buf[:0], kd.root())
+
+	for (len(stack)) > 0 {
+		q := stack[len(stack)-1]
+		stack = stack[:len(stack)-1] // .pop()
+
+		if q.left > q.right {
+			continue
+		}
+
+		switch classify(q.minX, q.minY, q.maxX, q.maxY) {
+		case boxOutside:
+			continue
+		case boxInside:
+			// all points inside, no need to test
+			if inside != nil {
+				if !inside(q.left, q.right) {
+					return
+				}
+				continue
+			}
+			for i := q.left; i <= q.right; i++ {
+				if !kd.DeletedAt(i) && !fn(i, float64(kd.coords[2*i]), float64(kd.coords[2*i+1])) {
+					return
+				}
+			}
+			continue
+		}
+
+		// search linearly
+		if q.right-q.left <= kd.nodeSize {
+			for i := q.left; i <= q.right; i++ {
+				x := float64(kd.coords[2*i])
+				y := float64(kd.coords[2*i+1])
+				if contains(x, y) && !kd.DeletedAt(i) && !fn(i, x, y) {
+					return
+				}
+			}
+			continue
+		}
+
+		// find in the middle index
+		m := (q.left + q.right) >> 1
+
+		// include middle item within shape
+		x := float64(kd.coords[2*m])
+		y := float64(kd.coords[2*m+1])
+		if contains(x, y) && !kd.DeletedAt(m) && !fn(m, x, y) {
+			return
+		}
+
+		// queue both halves, split bounding box by middle item
+		leftQuery := boxQuery{q.left, m - 1, 1 - q.axis, q.minX, q.minY, q.maxX, q.maxY}
+		rightQuery := boxQuery{m + 1, q.right, 1 - q.axis, q.minX, q.minY, q.maxX, q.maxY}
+		if q.axis == 0 {
+			leftQuery.maxX = x
+			rightQuery.minX = x
+		} else {
+			leftQuery.maxY = y
+			rightQuery.minY = y
+		}
+		stack = append(stack, leftQuery, rightQuery)
+	}
+}
+
+// root return top node of kd-tree with bounding box of all points
+func (kd *KDBushOf[C, I]) root() boxQuery {
+	return boxQuery{0, len(kd.ids) - 1, 0, kd.bounds[0], kd.bounds[1], kd.bounds[2], kd.bounds[3]}
+}
+
+// reset clear the index before rebuilding it with given nodeSize
+func (kd *KDBushOf[C, I]) reset(nodeSize int) {
+	kd.indexed = false
+	kd.nodeSize = nodeSize
+	kd.resetDeleted()
+	kd.Close()
+}
+
+// computeBounds compute bounding box of all points
+func (kd *KDBushOf[C, I]) computeBounds() {
+	kd.bounds = [4]float64{math.Inf(-1), math.Inf(-1), math.Inf(1), math.Inf(1)}
+	if len(kd.ids) == 0 {
+		return
+	}
 
-	return result
+	kd.bounds = [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for i := 0; i < len(kd.coords); i += 2 {
+		x := float64(kd.coords[i])
+		y := float64(kd.coords[i+1])
+		kd.bounds[0] = math.Min(kd.bounds[0], x)
+		kd.bounds[1] = math.Min(kd.bounds[1], y)
+		kd.bounds[2] = math.Max(kd.bounds[2], x)
+		kd.bounds[3] = math.Max(kd.bounds[3], y)
+	}
 }
 
 //
@@ -152,21 +472,21 @@ func (kd *KDBush) Within(qx, qy float64, radius float64) []int {
 //
 
 // GetNodeSize return current nodesize
-func (kd *KDBush) GetNodeSize() int {
+func (kd *KDBushOf[C, I]) GetNodeSize() int {
 	return kd.nodeSize
 }
 
 // GetIndexed return all kdtree indexes
-func (kd *KDBush) GetIndexes() []int {
+func (kd *KDBushOf[C, I]) GetIndexes() []I {
 	return kd.ids
 }
 
 // GetCoords return all coords
-func (kd *KDBush) GetCoords() []float64 {
+func (kd *KDBushOf[C, I]) GetCoords() []C {
 	return kd.coords
 }
 
 // Indexed return it's KDBush already indexed or not
-func (kd *KDBush) Indexed() bool {
+func (kd *KDBushOf[C, I]) Indexed() bool {
 	return kd.indexed
 }
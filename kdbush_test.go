@@ -173,3 +173,22 @@ func TestWithin(t *testing.T) {
 		}
 	}
 }
+
+// Test building index with more points than the index type can hold
+func TestBuildIndexTooManyPoints(t *testing.T) {
+	many := make([]kdbush.Point, 70000)
+	xs := make([]float32, len(many))
+	for i := range many {
+		many[i] = &kdbush.SimplePoint{X: float64(i), Y: float64(i)}
+		xs[i] = float32(i)
+	}
+
+	message := "kdbush: number of points can't fit in the index type"
+	assert.PanicsWithValue(t, message, func() { kdbush.NewBushOf[float32, uint16]().BuildIndex(many, 64) })
+	assert.PanicsWithValue(t, message, func() { kdbush.NewBushOf[float32, uint16]().BuildIndexParallel(many, 64, 2) })
+	assert.PanicsWithValue(t, message, func() { kdbush.NewBushOf[float32, uint16]().BuildIndexFromCoords(append(xs, xs...), 64) })
+	assert.PanicsWithValue(t, message, func() { kdbush.NewBushOf[float32, uint16]().BuildIndexFromXY(xs, xs, 64) })
+
+	bush := kdbush.NewBushOf[float32, uint16]().BuildIndex(many[:65536], 64)
+	assert.Equal(t, bush.Range(65534.5, 65534.5, 65536, 65536), []uint16{65535})
+}
@@ -0,0 +1,168 @@
+package kdbush
+
+// PointN interface will be used for KDBushN, point with any number of dimension
+type PointN interface {
+	Coord(i int) float64
+}
+
+// SimplePointN a basic point implement [PointN], each element is coordinate of a dimension
+type SimplePointN []float64
+
+func (p SimplePointN) Coord(i int) float64 {
+	return p[i]
+}
+
+// KDBushN an instance of k-dimensional KDBush, e.g. 3 for points with elevation or time
+type KDBushN struct {
+	dim      int
+	nodeSize int
+	ids      []int
+	coords   []float64
+	indexed  bool
+}
+
+// NewBushN return a new pointer of [KDBushN] with given number of dimension, panic if dim is less than 1
+func NewBushN(dim int) *KDBushN {
+	if dim < 1 {
+		panic("kdbush: dim must be greater than 0")
+	}
+	kd := KDBushN{dim: dim}
+	return &kd
+}
+
+// BuildIndex build kd-tree index given list of PointN, only first dim coordinates of each point are used
+func (kd *KDBushN) BuildIndex(points []PointN, nodeSize int) *KDBushN {
+	kd.indexed = false
+	kd.nodeSize = nodeSize
+
+	kd.ids = make([]int, len(points))
+	kd.coords = make([]float64, kd.dim*len(points))
+
+	for i, v := range points {
+		kd.ids[i] = i
+		for axis := 0; axis < kd.dim; axis++ {
+			kd.coords[i*kd.dim+axis] = v.Coord(axis)
+		}
+	}
+
+	sort(kd.ids, kd.coords, kd.nodeSize, 0, len(kd.ids)-1, 0, kd.dim)
+
+	kd.indexed = true
+	return kd
+}
+
+// Range returns all indexes points inside box between [min] and [max] corners, both should have dim length
+func (kd *KDBushN) Range(min, max []float64) []int {
+	result := []int{}
+
+	kd.search(func(axis int, v float64) (bool, bool) {
+		return min[axis] <= v, max[axis] >= v
+	}, func(i int) bool {
+		for axis := 0; axis < kd.dim; axis++ {
+			v := kd.coords[kd.dim*i+axis]
+			if v < min[axis] || v > max[axis] {
+				return false
+			}
+		}
+		return true
+	}, func(i int) {
+		result = append(result, kd.ids[i])
+	})
+
+	return result
+}
+
+// Within returns all indexes points within radius of given single point [q], q should have dim length
+func (kd *KDBushN) Within(q []float64, radius float64) []int {
+	result := []int{}
+	r2 := radius * radius
+
+	kd.search(func(axis int, v float64) (bool, bool) {
+		return q[axis]-radius <= v, q[axis]+radius >= v
+	}, func(i int) bool {
+		d2 := 0.0
+		for axis := 0; axis < kd.dim; axis++ {
+			d := kd.coords[kd.dim*i+axis] - q[axis]
+			d2 += d * d
+		}
+		return d2 <= r2
+	}, func(i int) {
+		result = append(result, kd.ids[i])
+	})
+
+	return result
+}
+
+// search traverse kd-tree, [halves] return the query intersect lower and/or upper half of node split at v on axis,
+// [contains] test point at position i, and [fn] called for each point found
+func (kd *KDBushN) search(halves func(axis int, v float64) (bool, bool), contains func(i int) bool, fn func(i int)) {
+	if !kd.indexed {
+		return
+	}
+
+	var buf [queryStackSize]query
+	stack := append(buf[:0], query{0, len(kd.ids) - 1, 0})
+
+	for (len(stack)) > 0 {
+		left := stack[len(stack)-1].left
+		right := stack[len(stack)-1].right
+		axis := stack[len(stack)-1].axis
+		stack = stack[:len(stack)-1] // .pop()
+
+		// search linearly
+		if right-left <= kd.nodeSize {
+			for i := left; i <= right; i++ {
+				if contains(i) {
+					fn(i)
+				}
+			}
+			continue
+		}
+
+		// find in the middle index
+		m := (left + right) >> 1
+
+		// include middle item within query
+		if contains(m) {
+			fn(m)
+		}
+
+		// queue search in halves that intersect the query
+		lower, upper := halves(axis, kd.coords[kd.dim*m+axis])
+		if lower {
+			stack = append(stack, query{left, m - 1, (axis + 1) % kd.dim})
+		}
+		if upper {
+			stack = append(stack, query{m + 1, right, (axis + 1) % kd.dim})
+		}
+	}
+}
+
+//
+// Helper get private param
+//
+
+// GetDim return number of dimension
+func (kd *KDBushN) GetDim() int {
+	return kd.dim
+}
+
+// GetNodeSize return current nodesize
+func (kd *KDBushN) GetNodeSize() int {
+	return kd.nodeSize
+}
+
+// GetIndexes return all kdtree indexes
+func (kd *KDBushN) GetIndexes() []int {
+	return kd.ids
+}
+
+// GetCoords return all coords, dim coordinates for each point
+func (kd *KDBushN) GetCoords() []float64 {
+	return kd.coords
+}
+
+// Indexed return it's KDBushN already indexed or not
+func (kd *KDBushN) Indexed() bool {
+	return kd.indexed
+}
@@ -0,0 +1,83 @@
+package kdbush_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test KDBushN Range & Within with brute force
+func TestKDBushN(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	for _, dim := range []int{1, 3, 4} {
+		_points := []kdbush.PointN{}
+		for i := 0; i < 2_000; i++ {
+			p := kdbush.SimplePointN{}
+			for axis := 0; axis < dim; axis++ {
+				p = append(p, rng.Float64()*100)
+			}
+			_points = append(_points, p)
+		}
+
+		for _, nodeSize := range []int{kdbush.STANDARD_NODE_SIZE, 4} {
+			bush := kdbush.NewBushN(dim).BuildIndex(_points, nodeSize)
+			assert.Equal(t, bush.GetDim(), dim)
+			assert.Equal(t, bush.GetNodeSize(), nodeSize)
+			assert.Equal(t, bush.Indexed(), true)
+			assert.Equal(t, len(bush.GetIndexes()), len(_points))
+			assert.Equal(t, len(bush.GetCoords()), len(_points)*dim)
+
+			for q := 0; q < 20; q++ {
+				min, max, center := []float64{}, []float64{}, []float64{}
+				for axis := 0; axis < dim; axis++ {
+					v := rng.Float64() * 100
+					min = append(min, v-20)
+					max = append(max, v+20)
+					center = append(center, v)
+				}
+
+				rangeIds, withinIds := []int{}, []int{}
+				for id, p := range _points {
+					inside := true
+					d2 := 0.0
+					for axis := 0; axis < dim; axis++ {
+						inside = inside && p.Coord(axis) >= min[axis] && p.Coord(axis) <= max[axis]
+						d2 += (p.Coord(axis) - center[axis]) * (p.Coord(axis) - center[axis])
+					}
+					if inside {
+						rangeIds = append(rangeIds, id)
+					}
+					if d2 <= 25*25 {
+						withinIds = append(withinIds, id)
+					}
+				}
+
+				assert.ElementsMatch(t, bush.Range(min, max), rangeIds, "range result should be same")
+				assert.ElementsMatch(t, bush.Within(center, 25), withinIds, "within result should be same")
+			}
+		}
+	}
+
+	bush := kdbush.NewBushN(3)
+	assert.Equal(t, bush.Range([]float64{0, 0, 0}, []float64{1, 1, 1}), []int{})
+	assert.Equal(t, bush.Within([]float64{0, 0, 0}, 1), []int{})
+
+	assert.Panics(t, func() { kdbush.NewBushN(0) })
+	assert.Panics(t, func() { kdbush.NewBushN(-1) })
+}
+
+// Test KDBushN with 2 dimension give same index with KDBush
+func TestKDBushNWith2D(t *testing.T) {
+	_points := []kdbush.PointN{}
+	for _, p := range points {
+		_points = append(_points, kdbush.SimplePointN{p.GetX(), p.GetY()})
+	}
+
+	expected := kdbush.NewBush().BuildIndex(points, 4)
+	bush := kdbush.NewBushN(2).BuildIndex(_points, 4)
+	assert.Equal(t, bush.GetIndexes(), expected.GetIndexes())
+	assert.Equal(t, bush.GetCoords(), expected.GetCoords())
+}
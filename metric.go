@@ -0,0 +1,140 @@
+package kdbush
+
+import "math"
+
+// Metric distance function between points, used by [KDBushOf.WithinMetric] & [KDBushOf.NearestMetric]
+type Metric interface {
+	// Distance return distance between point a and point b
+	Distance(ax, ay, bx, by float64) float64
+	// BoxDistance return lower bound of distance from a point to any point inside bounding box, 0 if the point is inside
+	BoxDistance(x, y, minX, minY, maxX, maxY float64) float64
+}
+
+// Euclidean straight-line (L2) distance
+type Euclidean struct{}
+
+// Manhattan sum of absolute differences (L1) distance, e.g. distance on a grid of streets
+type Manhattan struct{}
+
+// Chebyshev maximum of absolute differences (L∞) distance
+type Chebyshev struct{}
+
+// Minkowski generalized Lp distance with [P] >= 1, P = 1 is [Manhattan], P = 2 is [Euclidean] and P = +Inf is [Chebyshev]
+type Minkowski struct {
+	P float64
+}
+
+// Distance implements [Metric]
+func (Euclidean) Distance(ax, ay, bx, by float64) float64 {
+	return math.Hypot(ax-bx, ay-by)
+}
+
+// BoxDistance implements [Metric]
+func (Euclidean) BoxDistance(x, y, minX, minY, maxX, maxY float64) float64 {
+	return math.Sqrt(boxDist(x, y, minX, minY, maxX, maxY))
+}
+
+// Distance implements [Metric]
+func (Manhattan) Distance(ax, ay, bx, by float64) float64 {
+	return math.Abs(ax-bx) + math.Abs(ay-by)
+}
+
+// BoxDistance implements [Metric]
+func (Manhattan) BoxDistance(x, y, minX, minY, maxX, maxY float64) float64 {
+	return axisDist(x, minX, maxX) + axisDist(y, minY, maxY)
+}
+
+// Distance implements [Metric]
+func (Chebyshev) Distance(ax, ay, bx, by float64) float64 {
+	return max(math.Abs(ax-bx), math.Abs(ay-by))
+}
+
+// BoxDistance implements [Metric]
+func (Chebyshev) BoxDistance(x, y, minX, minY, maxX, maxY float64) float64 {
+	return max(axisDist(x, minX, maxX), axisDist(y, minY, maxY))
+}
+
+// Distance implements [Metric]
+func (m Minkowski) Distance(ax, ay, bx, by float64) float64 {
+	return m.norm(math.Abs(ax-bx), math.Abs(ay-by))
+}
+
+// BoxDistance implements [Metric]
+func (m Minkowski) BoxDistance(x, y, minX, minY, maxX, maxY float64) float64 {
+	return m.norm(axisDist(x, minX, maxX), axisDist(y, minY, maxY))
+}
+
+// norm Lp norm of absolute differences
+func (m Minkowski) norm(dx, dy float64) float64 {
+	switch {
+	case math.IsInf(m.P, 1):
+		return max(dx, dy)
+	case m.P == 1:
+		return dx + dy
+	case m.P == 2:
+		return math.Hypot(dx, dy)
+	}
+	return math.Pow(math.Pow(dx, m.P)+math.Pow(dy, m.P), 1/m.P)
+}
+
+// axisDist distance from a value to range of [lo, hi] on single axis, 0 if the value is inside
+func axisDist(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo - v
+	}
+	if v > hi {
+		return v - hi
+	}
+	return 0
+}
+
+// WithinMetric returns all indexes points within radius of given single [Point] measured by metric
+func (kd *KDBushOf[C, I]) WithinMetric(qx, qy float64, radius float64, metric Metric) []I {
+	result := []I{}
+	kd.WithinMetricFunc(qx, qy, radius, metric, func(id I, x, y float64) bool {
+		result = append(result, id)
+		return true
+	})
+	return result
+}
+
+// WithinMetricFunc calls fn for each point within radius of given single [Point] measured by metric.
+// If fn returns false, WithinMetricFunc stops the traversal
+func (kd *KDBushOf[C, I]) WithinMetricFunc(qx, qy float64, radius float64, metric Metric, fn func(id I, x, y float64) bool) {
+	kd.walk(func(minX, minY, maxX, maxY float64) int {
+		if metric.BoxDistance(qx, qy, minX, minY, maxX, maxY) > radius {
+			return boxOutside
+		}
+		return boxPartial
+	}, func(x, y float64) bool {
+		return metric.Distance(x, y, qx, qy) <= radius
+	}, func(i int, x, y float64) bool {
+		return fn(kd.ids[i], x, y)
+	}, nil)
+}
+
+// NearestMetric returns indexes of [k] closest points from given single [Point] measured by metric in order of increasing distance.
+// Use -1 on [k] for all points and -1 on [maxDist] for any distance. [filter] is optional to filter the indexes
+func (kd *KDBushOf[C, I]) NearestMetric(qx, qy float64, k int, maxDist float64, metric Metric, filter func(I) bool) []I {
+	result := []I{}
+	if k == 0 {
+		return result
+	}
+
+	kd.NearestMetricFunc(qx, qy, maxDist, metric, filter, func(id I, dist float64) bool {
+		result = append(result, id)
+		return len(result) != k
+	})
+
+	return result
+}
+
+// NearestMetricFunc calls fn for each point closest from given single [Point] measured by metric in order of increasing distance, with its distance.
+// If fn returns false, NearestMetricFunc stops the search
+func (kd *KDBushOf[C, I]) NearestMetricFunc(qx, qy float64, maxDist float64, metric Metric, filter func(I) bool, fn func(id I, dist float64) bool) {
+	if maxDist < 0 {
+		maxDist = math.Inf(1)
+	}
+
+	kd.nearest(qx, qy, maxDist, metric.Distance, metric.BoxDistance, filter, fn)
+}
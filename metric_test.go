@@ -0,0 +1,88 @@
+package kdbush_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+var metrics = map[string]kdbush.Metric{
+	"euclidean":   kdbush.Euclidean{},
+	"manhattan":   kdbush.Manhattan{},
+	"chebyshev":   kdbush.Chebyshev{},
+	"minkowski-3": kdbush.Minkowski{P: 3},
+}
+
+// Test Metric distances
+func TestMetricDistance(t *testing.T) {
+	assert.Equal(t, kdbush.Euclidean{}.Distance(0, 0, 3, 4), 5.0)
+	assert.Equal(t, kdbush.Manhattan{}.Distance(0, 0, 3, -4), 7.0)
+	assert.Equal(t, kdbush.Chebyshev{}.Distance(0, 0, 3, -4), 4.0)
+	assert.InDelta(t, kdbush.Minkowski{P: 3}.Distance(0, 0, 3, 4), math.Cbrt(27+64), 1e-9)
+	assert.Equal(t, kdbush.Minkowski{P: 1}.Distance(0, 0, 3, 4), 7.0)
+	assert.Equal(t, kdbush.Minkowski{P: 2}.Distance(0, 0, 3, 4), 5.0)
+	assert.Equal(t, kdbush.Minkowski{P: math.Inf(1)}.Distance(0, 0, 3, 4), 4.0)
+
+	for name, metric := range metrics {
+		assert.Equal(t, metric.BoxDistance(1, 1, 0, 0, 2, 2), 0.0, name+" should be 0 inside box")
+		assert.InDelta(t, metric.BoxDistance(5, 1, 0, 0, 2, 2), 3.0, 1e-9, name+" should be distance to nearest edge")
+		assert.Equal(t, metric.BoxDistance(5, 6, 0, 0, 2, 2), metric.Distance(5, 6, 2, 2), name+" should be distance to nearest corner")
+	}
+}
+
+// Test WithinMetric & NearestMetric against brute force
+func TestMetricQuery(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	_points := []kdbush.Point{}
+	for i := 0; i < 2_000; i++ {
+		_points = append(_points, &kdbush.SimplePoint{rng.Float64() * 100, rng.Float64() * 100})
+	}
+	bush := kdbush.NewBush().BuildIndex(_points, 8)
+
+	for name, metric := range metrics {
+		for q := 0; q < 20; q++ {
+			qx, qy, r := rng.Float64()*100, rng.Float64()*100, rng.Float64()*20
+
+			expected := []int{}
+			for i, p := range _points {
+				if metric.Distance(p.GetX(), p.GetY(), qx, qy) <= r {
+					expected = append(expected, i)
+				}
+			}
+			assert.ElementsMatch(t, bush.WithinMetric(qx, qy, r, metric), expected, name+" within should be same with brute force")
+
+			nearest := bush.NearestMetric(qx, qy, 10, r, metric, nil)
+			assert.Equal(t, len(nearest), min(10, len(expected)))
+			for i := 1; i < len(nearest); i++ {
+				a := _points[nearest[i-1]]
+				b := _points[nearest[i]]
+				assert.LessOrEqual(t, metric.Distance(a.GetX(), a.GetY(), qx, qy), metric.Distance(b.GetX(), b.GetY(), qx, qy), name+" nearest should be in order of increasing distance")
+			}
+			if len(nearest) > 0 {
+				last := _points[nearest[len(nearest)-1]]
+				lastDist := metric.Distance(last.GetX(), last.GetY(), qx, qy)
+				closer := 0
+				for _, i := range expected {
+					if metric.Distance(_points[i].GetX(), _points[i].GetY(), qx, qy) < lastDist {
+						closer++
+					}
+				}
+				assert.Less(t, closer, len(nearest), name+" nearest should be the closest points")
+			}
+		}
+	}
+
+	assert.ElementsMatch(t, bush.WithinMetric(50, 50, 10, kdbush.Euclidean{}), bush.Within(50, 50, 10), "euclidean should be same with Within")
+	assert.Equal(t, bush.NearestMetric(50, 50, 20, -1, kdbush.Euclidean{}, nil), bush.Nearest(50, 50, 20, -1, nil), "euclidean should be same with Nearest")
+	assert.Equal(t, bush.NearestMetric(50, 50, 0, -1, kdbush.Manhattan{}, nil), []int{})
+
+	bush.NearestMetricFunc(50, 50, -1, kdbush.Manhattan{}, nil, func(id int, dist float64) bool {
+		p := _points[id]
+		assert.Equal(t, dist, kdbush.Manhattan{}.Distance(p.GetX(), p.GetY(), 50, 50), "it should report metric distance")
+		return false
+	})
+}
@@ -0,0 +1,64 @@
+package kdbush
+
+// Open memory-map index file written by [KDBushOf.WriteFile] (Javascript KDBush v4 format) and return read-only index
+// with float64 coords and uint32 ids, so both point straight into the mapping for index of 65536 points or more
+// (ids of smaller index are uint16 in the file and copied). See [OpenOf] for more detail
+func Open(path string) (*KDBushOf[float64, uint32], error) {
+	return OpenOf[float64, uint32](path)
+}
+
+// OpenOf memory-map index file written by [KDBushOf.WriteFile] (Javascript KDBush v4 format) and return read-only [KDBushOf],
+// without copying the index into the heap. Coords point straight into the mapping when the array type is same with C,
+// and so are ids when I is uint16 (less than 65536 points) or uint32, otherwise they are converted into the heap.
+//
+// The index must be closed by [KDBushOf.Close] once it is no longer used.
+// Rebuilding the index (e.g. [KDBushOf.Finish] or [KDBushOf.Compact]) copies it into the heap and releases the mapping
+func OpenOf[C Coord, I ID](path string) (*KDBushOf[C, I], error) {
+	data, err := mmapFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	kd := NewBushOf[C, I]()
+	mapped, err := kd.decode(data, true)
+	if err != nil || !mapped {
+		if unmapErr := munmapFile(data); err == nil {
+			err = unmapErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		return kd, nil
+	}
+
+	kd.mapping = data
+	return kd, nil
+}
+
+// Close release the mapping of index loaded by [OpenOf], the index is no longer usable after.
+// It does nothing for index that is not mapped
+func (kd *KDBushOf[C, I]) Close() error {
+	if kd.mapping == nil {
+		return nil
+	}
+
+	err := munmapFile(kd.mapping)
+	kd.mapping = nil
+	kd.indexed = false
+	kd.ids = nil
+	kd.coords = nil
+	kd.resetDeleted()
+	return err
+}
+
+// unmap copy ids & coords that point into the mapping into the heap, then release the mapping
+func (kd *KDBushOf[C, I]) unmap() {
+	if kd.mapping == nil {
+		return
+	}
+
+	kd.ids = append(make([]I, 0, len(kd.ids)), kd.ids...)
+	kd.coords = append(make([]C, 0, len(kd.coords)), kd.coords...)
+	munmapFile(kd.mapping)
+	kd.mapping = nil
+}
@@ -0,0 +1,22 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package kdbush
+
+import "os"
+
+// mmapFile read whole file into memory, memory-mapping is not supported on this platform
+func mmapFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < binaryHeaderSize {
+		return nil, ErrInvalidData
+	}
+	return data, nil
+}
+
+// munmapFile release mapping of mmapFile, nothing to do since it is not mapped
+func munmapFile(data []byte) error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package kdbush_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test WriteFile & Open give same index
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.kdbush")
+
+	expected := kdbush.NewBush().BuildIndex(points, 4)
+	assert.Nil(t, expected.WriteFile(path))
+
+	bush, err := kdbush.Open(path)
+	assert.Nil(t, err)
+	assert.Equal(t, bush.Indexed(), true, "should indexed")
+	assert.Equal(t, bush.GetNodeSize(), 4, "nodesize should be same")
+	assert.Equal(t, toInts(bush.GetIndexes()), expected.GetIndexes(), "indexes should be same")
+	assert.Equal(t, bush.GetCoords(), expected.GetCoords(), "coords should be same")
+	assert.Equal(t, toInts(bush.Range(-2.1, 1, 2.1, 2)), expected.Range(-2.1, 1, 2.1, 2))
+	assert.Equal(t, toInts(bush.Within(0.3, 0.2, 0.8)), expected.Within(0.3, 0.2, 0.8))
+	assert.Nil(t, bush.Close())
+	assert.Equal(t, bush.Indexed(), false, "should not indexed after close")
+	assert.Equal(t, bush.Range(-10, -10, 10, 10), []uint32{})
+	assert.Nil(t, bush.Close(), "close twice should do nothing")
+
+	// ids & coords point into the mapping
+	mapped, err := kdbush.OpenOf[float64, uint16](path)
+	assert.Nil(t, err)
+	assert.Equal(t, toInts(mapped.GetIndexes()), expected.GetIndexes(), "indexes should be same")
+	assert.Equal(t, toInts(mapped.Nearest(0.1, 0.2, 5, -1, nil)), expected.Nearest(0.1, 0.2, 5, -1, nil))
+
+	// rebuild should copy the index out of the mapping
+	mapped.Delete(mapped.Range(0, 0, 0, 0)[0])
+	assert.True(t, mapped.SetCompactRatio(0).Compact())
+	assert.Equal(t, mapped.Add(100, 100), len(points))
+	mapped.Finish()
+	assert.Equal(t, mapped.Live(), len(points))
+	assert.Equal(t, mapped.Range(100, 100, 100, 100), []uint16{uint16(len(points))})
+	assert.Nil(t, mapped.Close())
+	assert.Equal(t, mapped.Indexed(), true, "should still indexed after rebuilt")
+
+	// different coordinate type
+	converted, err := kdbush.OpenOf[int8, uint32](path)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, toInts(converted.Range(-2.1, 1, 2.1, 2)), expected.Range(-2.1, 1, 2.1, 2))
+	assert.Nil(t, converted.Close())
+
+	// invalid files
+	_, err = kdbush.Open(filepath.Join(t.TempDir(), "not-exist.kdbush"))
+	assert.True(t, os.IsNotExist(err))
+
+	invalid := filepath.Join(t.TempDir(), "invalid.kdbush")
+	assert.Nil(t, os.WriteFile(invalid, []byte{0xdb, 0x18}, 0o644))
+	_, err = kdbush.Open(invalid)
+	assert.Equal(t, err, kdbush.ErrInvalidData)
+
+	assert.Nil(t, os.WriteFile(invalid, []byte{0xdb, 0x18, 0, 0, 1, 0, 0, 0}, 0o644))
+	_, err = kdbush.Open(invalid)
+	assert.Equal(t, err, kdbush.ErrInvalidData)
+}
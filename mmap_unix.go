@@ -0,0 +1,35 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package kdbush
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile map whole file into memory as read-only
+func mmapFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < binaryHeaderSize {
+		return nil, ErrInvalidData
+	}
+	if int64(int(info.Size())) != info.Size() {
+		return nil, ErrIndexTooLarge
+	}
+
+	return syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// munmapFile release mapping of mmapFile
+func munmapFile(data []byte) error {
+	return syscall.Munmap(data)
+}
@@ -0,0 +1,43 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package kdbush_test
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test Open keep ids & coords in the mapping, so changes of the file are visible without reloading
+func TestOpenMapped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.kdbush")
+
+	many := make([]kdbush.Point, 70000)
+	for i := range many {
+		many[i] = &kdbush.SimplePoint{X: float64(i), Y: float64(-i)}
+	}
+	assert.Nil(t, kdbush.NewBush().BuildIndex(many, 64).WriteFile(path))
+
+	bush, err := kdbush.Open(path)
+	assert.Nil(t, err)
+	defer bush.Close()
+
+	// overwrite first id (uint32 after 8 bytes header) & first x (float64 after ids & padding) of the file
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	assert.Nil(t, err)
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint32(buf, 123456)
+	_, err = f.WriteAt(buf[:4], 8)
+	assert.Nil(t, err)
+	binary.LittleEndian.PutUint64(buf, 0x4059000000000000) // 100.0
+	_, err = f.WriteAt(buf, int64(8+4*len(many)))
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+
+	assert.Equal(t, bush.GetIndexes()[0], uint32(123456), "ids should point into the mapping")
+	assert.Equal(t, bush.GetCoords()[0], 100.0, "coords should point into the mapping")
+}
@@ -0,0 +1,185 @@
+package kdbush
+
+import (
+	"container/heap"
+	"math"
+)
+
+// nullInt simple nullable int
+type nullInt struct {
+	Int   int
+	Valid bool
+}
+
+// node for nearest search, either a point or a kd-tree node with its bounding box
+type node struct {
+	// item position of point in kd-tree array, invalid for kd-tree node
+	item nullInt
+
+	left  int
+	right int
+	axis  int
+	dist  float64
+	minX  float64
+	minY  float64
+	maxX  float64
+	maxY  float64
+
+	// Queue index
+	index int
+}
+
+// nodeQueue a priority queue by distance
+// Example of Priority Queue is using heap std, see more at https://pkg.go.dev/container/heap#example__priorityQueue
+type nodeQueue []*node
+
+func (q nodeQueue) Len() int { return len(q) }
+
+func (q nodeQueue) Less(i, j int) bool {
+	return q[i].dist < q[j].dist
+}
+
+func (q nodeQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *nodeQueue) Push(x any) {
+	n := len(*q)
+	item := x.(*node)
+	item.index = n
+	*q = append(*q, item)
+}
+
+func (q *nodeQueue) Pop() any {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil  // avoid memory leak
+	item.index = -1 // for safety
+	*q = old[0 : n-1]
+	return item
+}
+
+// Nearest returns indexes of [k] closest points from given single [Point] in order of increasing distance.
+// Use -1 on [k] for all points and -1 on [maxDist] for any distance. [filter] is optional to filter the indexes
+func (kd *KDBushOf[C, I]) Nearest(qx, qy float64, k int, maxDist float64, filter func(I) bool) []I {
+	result := []I{}
+	if k == 0 {
+		return result
+	}
+
+	kd.NearestFunc(qx, qy, maxDist, filter, func(id I, dist float64) bool {
+		result = append(result, id)
+		return len(result) != k
+	})
+
+	return result
+}
+
+// NearestFunc calls fn for each point closest from given single [Point] in order of increasing distance, with its squared distance.
+// If fn returns false, NearestFunc stops the search
+func (kd *KDBushOf[C, I]) NearestFunc(qx, qy float64, maxDist float64, filter func(I) bool, fn func(id I, dist float64) bool) {
+	maxSqDist := math.Inf(1)
+	if maxDist >= 0 {
+		maxSqDist = maxDist * maxDist
+	}
+
+	kd.nearest(qx, qy, maxSqDist, sqrtDist, boxDist, filter, fn)
+}
+
+// nearest search points closest from given single [Point] in order of increasing distance, with [pointDist] of point and [nodeDist] as lower bound of node.
+// Both distances and [maxDist] are in the same unit, calls fn until it returns false
+func (kd *KDBushOf[C, I]) nearest(qx, qy float64, maxDist float64, pointDist func(ax, ay, bx, by float64) float64, nodeDist func(x, y, minX, minY, maxX, maxY float64) float64, filter func(I) bool, fn func(id I, dist float64) bool) {
+	if !kd.indexed {
+		return
+	}
+
+	// a distance-sorted priority queue that will contain both points and kd-tree nodes
+	q := nodeQueue{}
+	heap.Init(&q)
+
+	// top kd-tree node, bounding box of whole plane
+	n := &node{
+		left:  0,
+		right: len(kd.ids) - 1,
+		axis:  0,
+		minX:  math.Inf(-1),
+		minY:  math.Inf(-1),
+		maxX:  math.Inf(1),
+		maxY:  math.Inf(1),
+	}
+
+	for n != nil {
+		right := n.right
+		left := n.left
+
+		if right-left <= kd.nodeSize {
+			// leaf node, add all points to the queue
+			for i := left; i <= right; i++ {
+				if !kd.DeletedAt(i) && (filter == nil || filter(kd.ids[i])) {
+					heap.Push(&q, &node{
+						item: nullInt{i, true},
+						dist: pointDist(float64(kd.coords[2*i]), float64(kd.coords[2*i+1]), qx, qy),
+					})
+				}
+			}
+		} else {
+			// not a leaf node (has child nodes)
+			m := (left + right) >> 1
+			x := float64(kd.coords[2*m])
+			y := float64(kd.coords[2*m+1])
+
+			// add middle point to the queue
+			if !kd.DeletedAt(m) && (filter == nil || filter(kd.ids[m])) {
+				heap.Push(&q, &node{
+					item: nullInt{m, true},
+					dist: pointDist(x, y, qx, qy),
+				})
+			}
+
+			// both halves of the node
+			leftNode := &node{left: left, right: m - 1, axis: 1 - n.axis, minX: n.minX, minY: n.minY, maxX: n.maxX, maxY: n.maxY}
+			rightNode := &node{left: m + 1, right: right, axis: 1 - n.axis, minX: n.minX, minY: n.minY, maxX: n.maxX, maxY: n.maxY}
+
+			if n.axis == 0 {
+				leftNode.maxX = x
+				rightNode.minX = x
+			} else {
+				leftNode.maxY = y
+				rightNode.minY = y
+			}
+
+			leftNode.dist = nodeDist(qx, qy, leftNode.minX, leftNode.minY, leftNode.maxX, leftNode.maxY)
+			rightNode.dist = nodeDist(qx, qy, rightNode.minX, rightNode.minY, rightNode.maxX, rightNode.maxY)
+
+			// add child nodes to the queue, skip the empty & too far one
+			if leftNode.left <= leftNode.right && leftNode.dist <= maxDist {
+				heap.Push(&q, leftNode)
+			}
+			if rightNode.left <= rightNode.right && rightNode.dist <= maxDist {
+				heap.Push(&q, rightNode)
+			}
+		}
+
+		// fetch closest points from the queue; they're guaranteed to be closer than all remaining points, since each node's distance is a lower bound of distances to its children
+		for len(q) > 0 && q[0].item.Valid {
+			candidate := heap.Pop(&q).(*node)
+			if candidate.dist > maxDist {
+				return
+			}
+
+			if !fn(kd.ids[candidate.item.Int], candidate.dist) {
+				return
+			}
+		}
+
+		// the next closest kd-tree node
+		if len(q) > 0 {
+			n = heap.Pop(&q).(*node)
+		} else {
+			n = nil
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package kdbush_test
+
+import (
+	"math/rand"
+	gosort "sort"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test Nearest func with brute force
+func TestNearest(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	_points := []kdbush.Point{}
+	for i := 0; i < 1_000; i++ {
+		_points = append(_points, &kdbush.SimplePoint{rng.Float64() * 100, rng.Float64() * 100})
+	}
+
+	testCases := []struct {
+		X, Y    float64
+		K       int
+		MaxDist float64
+		Filter  func(int) bool
+	}{
+		{50, 50, 10, -1, nil},
+		{0, 0, 1, -1, nil},
+		{-50, 150, 5, -1, nil},
+		{50, 50, -1, 10, nil},
+		{25, 75, 20, 5, nil},
+		{50, 50, 10, -1, func(id int) bool { return id%2 == 0 }},
+		{50, 50, -1, -1, nil},
+	}
+
+	for _, nodeSize := range []int{kdbush.STANDARD_NODE_SIZE, 4} {
+		bush := kdbush.NewBush().BuildIndex(_points, nodeSize)
+
+		for _, testCase := range testCases {
+			expected := bruteNearest(_points, testCase.X, testCase.Y, testCase.K, testCase.MaxDist, testCase.Filter)
+			result := bush.Nearest(testCase.X, testCase.Y, testCase.K, testCase.MaxDist, testCase.Filter)
+			assert.Equal(t, len(result), len(expected), "it should be has same count result")
+
+			for i := range result {
+				assert.Equal(t, sqDist(_points[result[i]], testCase.X, testCase.Y), sqDist(_points[expected[i]], testCase.X, testCase.Y), "it should be has same distance order")
+			}
+		}
+	}
+
+	bush := kdbush.NewBush().BuildIndex(points, 4)
+	assert.Equal(t, bush.Nearest(0, 0, 0, -1, nil), []int{})
+	assert.Equal(t, kdbush.NewBush().Nearest(0, 0, 1, -1, nil), []int{})
+	assert.ElementsMatch(t, bush.Nearest(0, 0, 5, -1, nil), bush.Within(0, 0, 1))
+
+	// NearestFunc with squared distance
+	last := 0.0
+	count := 0
+	bush.NearestFunc(0.1, 0.2, 3, nil, func(id int, dist float64) bool {
+		assert.Equal(t, dist, sqDist(points[id], 0.1, 0.2))
+		assert.GreaterOrEqual(t, dist, last)
+		last = dist
+		count++
+		return true
+	})
+	assert.Equal(t, count, len(bush.Within(0.1, 0.2, 3)))
+}
+
+func sqDist(p kdbush.Point, x, y float64) float64 {
+	dx := p.GetX() - x
+	dy := p.GetY() - y
+	return dx*dx + dy*dy
+}
+
+func bruteNearest(points []kdbush.Point, x, y float64, k int, maxDist float64, filter func(int) bool) []int {
+	result := []int{}
+	for i, p := range points {
+		if (maxDist < 0 || sqDist(p, x, y) <= maxDist*maxDist) && (filter == nil || filter(i)) {
+			result = append(result, i)
+		}
+	}
+	gosort.SliceStable(result, func(i, j int) bool {
+		return sqDist(points[result[i]], x, y) < sqDist(points[result[j]], x, y)
+	})
+	if k >= 0 && len(result) > k {
+		result = result[:k]
+	}
+	return result
+}
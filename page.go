@@ -0,0 +1,113 @@
+package kdbush
+
+import "math/rand"
+
+// RangePage returns a page of indexes points across [minX], [minY], [maxX], [maxY] after skipping [offset] points, at most [limit] points (-1 for no limit).
+// Points are in stable order of their position in the index. Nodes entirely inside the query are skipped at once without testing their points.
+// It also returns cursor to continue with [KDBushOf.RangeAfter] without skipping again, or -1 if there is no more point.
+// Zero limit returns empty page with cursor at the first point after the offset
+func (kd *KDBushOf[C, I]) RangePage(minX, minY, maxX, maxY float64, offset, limit int) ([]I, int) {
+	return kd.rangePage(minX, minY, maxX, maxY, 0, offset, limit)
+}
+
+// RangeAfter returns next page of at most [limit] points (-1 for no limit) continuing from cursor of [KDBushOf.RangePage] or previous RangeAfter,
+// with cursor of the next page or -1 if there is no more point. Traversal before the cursor is pruned, so the query is not run again from the start.
+// Cursor is valid until the index is rebuilt, e.g. by [KDBushOf.Finish] or [KDBushOf.Compact]. Zero limit returns empty page with cursor at the next point
+func (kd *KDBushOf[C, I]) RangeAfter(minX, minY, maxX, maxY float64, cursor, limit int) ([]I, int) {
+	if cursor < 0 {
+		return []I{}, -1
+	}
+	return kd.rangePage(minX, minY, maxX, maxY, cursor, 0, limit)
+}
+
+// rangePage traverse kd-tree in order of position starting from position [start], skip [skip] points and collect at most [limit] points.
+// Zero limit stops at the first point to collect, so the cursor is still -1 only if there is no more point
+func (kd *KDBushOf[C, I]) rangePage(minX, minY, maxX, maxY float64, start, skip, limit int) ([]I, int) {
+	result := []I{}
+	if !kd.indexed {
+		return result, -1
+	}
+
+	var buf [queryStackSize]boxQuery
+	stack := append(buf[:0], kd.root())
+
+	for len(stack) > 0 {
+		q := stack[len(stack)-1]
+		stack = stack[:len(stack)-1] // .pop()
+
+		// whole node before the cursor
+		if q.left > q.right || q.right < start {
+			continue
+		}
+		left := max(q.left, start)
+
+		position := classifyRange(minX, minY, maxX, maxY, q.minX, q.minY, q.maxX, q.maxY)
+		if position == boxOutside {
+			continue
+		}
+
+		// skip whole node inside the query
+		if position == boxInside && skip > 0 {
+			if n := q.right - left + 1 - kd.deletedBetween(left, q.right); n <= skip {
+				skip -= n
+				continue
+			}
+		}
+
+		// search linearly in order
+		if position == boxInside || kd.isLeaf(q) {
+			for i := left; i <= q.right; i++ {
+				x := float64(kd.coords[2*i])
+				y := float64(kd.coords[2*i+1])
+				if x < minX || x > maxX || y < minY || y > maxY || kd.DeletedAt(i) {
+					continue
+				}
+				if skip > 0 {
+					skip--
+					continue
+				}
+				if limit == 0 {
+					return result, i
+				}
+
+				result = append(result, kd.ids[i])
+				if len(result) == limit {
+					if i+1 >= len(kd.ids) {
+						return result, -1
+					}
+					return result, i + 1
+				}
+			}
+			continue
+		}
+
+		// queue left half, middle point and right half, so they are popped in order of position
+		children := kd.split(q)
+		stack = append(stack, children[2], children[1], children[0])
+	}
+
+	return result, -1
+}
+
+// RangeSample returns uniform random sample of at most [n] indexes points across [minX], [minY], [maxX], [maxY] using reservoir sampling,
+// so matches are not collected. Same seed returns same sample
+func (kd *KDBushOf[C, I]) RangeSample(minX, minY, maxX, maxY float64, n int, seed int64) []I {
+	result := []I{}
+	if n <= 0 {
+		return result
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	seen := 0
+	kd.RangeFunc(minX, minY, maxX, maxY, func(id I, x, y float64) bool {
+		seen++
+		if len(result) < n {
+			result = append(result, id)
+		} else if j := rng.Intn(seen); j < n {
+			result[j] = id
+		}
+		return true
+	})
+
+	return result
+}
@@ -0,0 +1,118 @@
+package kdbush_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test RangePage & RangeAfter page through all points of Range
+func TestRangePage(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	_points := []kdbush.Point{}
+	for i := 0; i < 10_000; i++ {
+		_points = append(_points, &kdbush.SimplePoint{X: rng.Float64() * 100, Y: rng.Float64() * 100})
+	}
+
+	for _, nodeSize := range []int{kdbush.STANDARD_NODE_SIZE, 4} {
+		bush := kdbush.NewBush().BuildIndex(_points, nodeSize)
+		for i := 0; i < len(_points); i += 5 {
+			bush.Delete(i)
+		}
+
+		for q := 0; q < 10; q++ {
+			minX, minY := rng.Float64()*80, rng.Float64()*80
+			maxX, maxY := minX+rng.Float64()*40, minY+rng.Float64()*40
+
+			all, cursor := bush.RangePage(minX, minY, maxX, maxY, 0, -1)
+			assert.Equal(t, cursor, -1, "it should be the last page")
+			assert.ElementsMatch(t, all, bush.Range(minX, minY, maxX, maxY), "it should be same with Range")
+
+			// by offset
+			for _, limit := range []int{1, 7, 100} {
+				for offset := 0; offset < len(all)+limit; offset += limit {
+					page, _ := bush.RangePage(minX, minY, maxX, maxY, offset, limit)
+					assert.Equal(t, page, all[min(offset, len(all)):min(offset+limit, len(all))], "page should be in stable order")
+				}
+			}
+
+			// by cursor
+			paged := []int{}
+			page, cursor := bush.RangePage(minX, minY, maxX, maxY, 0, 50)
+			paged = append(paged, page...)
+			for cursor >= 0 {
+				assert.LessOrEqual(t, len(page), 50)
+				page, cursor = bush.RangeAfter(minX, minY, maxX, maxY, cursor, 50)
+				paged = append(paged, page...)
+			}
+			assert.Equal(t, paged, all, "pages should be same with all points")
+
+			page, cursor = bush.RangePage(minX, minY, maxX, maxY, 10, 50)
+			next, _ := bush.RangeAfter(minX, minY, maxX, maxY, cursor, 50)
+			expected, _ := bush.RangePage(minX, minY, maxX, maxY, 60, 50)
+			assert.Equal(t, next, expected, "cursor should continue after offset")
+		}
+	}
+
+	bush := kdbush.NewBush().BuildIndex(points, 10)
+	page, cursor := bush.RangeAfter(0, 0, 10, 10, -1, 10)
+	assert.Equal(t, page, []int{})
+	assert.Equal(t, cursor, -1)
+
+	// zero limit keeps the cursor, so the next page continues after the offset
+	page, cursor = bush.RangePage(0, 0, 10, 10, 5, 0)
+	assert.Equal(t, page, []int{})
+	assert.GreaterOrEqual(t, cursor, 0, "it should not be exhausted")
+	next, _ := bush.RangeAfter(0, 0, 10, 10, cursor, 3)
+	expected, _ := bush.RangePage(0, 0, 10, 10, 5, 3)
+	assert.Equal(t, next, expected)
+
+	page, again := bush.RangeAfter(0, 0, 10, 10, cursor, 0)
+	assert.Equal(t, page, []int{})
+	assert.Equal(t, again, cursor, "it should stay at the same point")
+
+	total := len(bush.Range(0, 0, 10, 10))
+	page, cursor = bush.RangePage(0, 0, 10, 10, total, 0)
+	assert.Equal(t, page, []int{})
+	assert.Equal(t, cursor, -1, "it should be exhausted after all points")
+}
+
+// Test RangeSample
+func TestRangeSample(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, 10)
+	all := bush.Range(-3, -2, 5, 7)
+
+	sample := bush.RangeSample(-3, -2, 5, 7, 10, 1)
+	assert.Equal(t, len(sample), 10)
+	assert.Subset(t, all, sample, "sample should be inside range")
+	assert.Equal(t, len(toSet(sample)), 10, "sample should not have duplicate")
+	assert.Equal(t, bush.RangeSample(-3, -2, 5, 7, 10, 1), sample, "same seed should return same sample")
+	assert.NotEqual(t, bush.RangeSample(-3, -2, 5, 7, 10, 2), sample)
+
+	assert.ElementsMatch(t, bush.RangeSample(-3, -2, 5, 7, len(all)+10, 1), all, "it should return all points when n is larger")
+	assert.Equal(t, bush.RangeSample(-3, -2, 5, 7, 0, 1), []int{})
+
+	// every point should be picked with same probability
+	picked := map[int]int{}
+	for seed := int64(0); seed < 2_000; seed++ {
+		for _, id := range bush.RangeSample(-3, -2, 5, 7, 5, seed) {
+			picked[id]++
+		}
+	}
+	expected := 2_000 * 5 / float64(len(all))
+	for _, id := range all {
+		assert.InDelta(t, float64(picked[id]), expected, expected*0.6, "it should be uniform")
+	}
+}
+
+// toSet return set of ids
+func toSet(ids []int) map[int]bool {
+	result := map[int]bool{}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result
+}
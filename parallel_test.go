@@ -0,0 +1,37 @@
+package kdbush_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test parallel build give identical index with serial build
+func TestBuildIndexParallel(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	_points := []kdbush.Point{}
+	for i := 0; i < 200_000; i++ {
+		_points = append(_points, &kdbush.SimplePoint{rng.Float64()*24.0 + 24.0, float64(rng.Intn(100))})
+	}
+
+	for _, nodeSize := range []int{kdbush.STANDARD_NODE_SIZE, 8} {
+		expected := kdbush.NewBush().BuildIndex(_points, nodeSize)
+
+		for _, workers := range []int{0, 1, 3, 8} {
+			bush := kdbush.NewBush().BuildIndexParallel(_points, nodeSize, workers)
+			assert.Equal(t, bush.Indexed(), true, "should indexed")
+			assert.Equal(t, bush.GetIndexes(), expected.GetIndexes(), "indexes should be identical")
+			assert.Equal(t, bush.GetCoords(), expected.GetCoords(), "coords should be identical")
+		}
+	}
+
+	bush := kdbush.NewBushWithCapacity(len(points), 4)
+	for _, p := range points {
+		bush.Add(p.GetX(), p.GetY())
+	}
+	assert.Equal(t, bush.FinishParallel(4).GetIndexes(), kdbush.NewBush().BuildIndex(points, 4).GetIndexes())
+	assert.Equal(t, kdbush.NewBush().BuildIndexParallel(nil, 4, 4).Range(-1, -1, 1, 1), []int{})
+}
@@ -0,0 +1,158 @@
+package kdbush
+
+import "math"
+
+// Polygon returns all indexes points inside polygon.
+// First ring of [rings] is the outer ring and the rest are holes, ring can be closed or not.
+// Points exactly on the boundary may or may not be included
+func (kd *KDBushOf[C, I]) Polygon(rings [][][2]float64) []I {
+	return kd.MultiPolygon([][][][2]float64{rings})
+}
+
+// MultiPolygon returns all indexes points inside any of polygons, see [KDBushOf.Polygon]
+func (kd *KDBushOf[C, I]) MultiPolygon(polygons [][][][2]float64) []I {
+	result := []I{}
+
+	q := newPolygonQuery(polygons)
+	kd.walk(q.classify, q.contains, func(i int, x, y float64) bool {
+		result = append(result, kd.ids[i])
+		return true
+	}, nil)
+
+	return result
+}
+
+// polygonQuery helper struct for API Polygon & MultiPolygon
+type polygonQuery struct {
+	polygons [][][][2]float64
+	bboxes   [][4]float64
+}
+
+// newPolygonQuery return polygonQuery with bounding box of each polygon
+func newPolygonQuery(polygons [][][][2]float64) *polygonQuery {
+	q := polygonQuery{
+		polygons: polygons,
+		bboxes:   make([][4]float64, len(polygons)),
+	}
+
+	for i, rings := range polygons {
+		bbox := [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+		if len(rings) > 0 {
+			// holes are inside outer ring, so outer ring is enough
+			for _, p := range rings[0] {
+				bbox[0] = math.Min(bbox[0], p[0])
+				bbox[1] = math.Min(bbox[1], p[1])
+				bbox[2] = math.Max(bbox[2], p[0])
+				bbox[3] = math.Max(bbox[3], p[1])
+			}
+		}
+		q.bboxes[i] = bbox
+	}
+
+	return &q
+}
+
+// contains return point inside any of polygons or not
+func (q *polygonQuery) contains(x, y float64) bool {
+	for i, rings := range q.polygons {
+		bbox := q.bboxes[i]
+		if x >= bbox[0] && x <= bbox[2] && y >= bbox[1] && y <= bbox[3] && pointInPolygon(x, y, rings) {
+			return true
+		}
+	}
+	return false
+}
+
+// classify return position of bounding box against polygons, inside any polygon, outside all polygons or partially
+func (q *polygonQuery) classify(minX, minY, maxX, maxY float64) int {
+	result := boxOutside
+
+	for i, rings := range q.polygons {
+		bbox := q.bboxes[i]
+
+		// clip box with bounding box of polygon, there is no part of polygon outside it
+		cMinX := math.Max(minX, bbox[0])
+		cMinY := math.Max(minY, bbox[1])
+		cMaxX := math.Min(maxX, bbox[2])
+		cMaxY := math.Min(maxY, bbox[3])
+		if cMinX > cMaxX || cMinY > cMaxY {
+			continue
+		}
+
+		if polygonIntersectsBox(rings, cMinX, cMinY, cMaxX, cMaxY) {
+			result = boxPartial
+			continue
+		}
+
+		// no edge across the clipped box, so it is either entirely inside or outside the polygon
+		if !pointInPolygon((cMinX+cMaxX)/2, (cMinY+cMaxY)/2, rings) {
+			continue
+		}
+		if cMinX == minX && cMinY == minY && cMaxX == maxX && cMaxY == maxY {
+			return boxInside
+		}
+		result = boxPartial
+	}
+
+	return result
+}
+
+// pointInPolygon test point inside polygon with holes using ray casting (even-odd rule)
+func pointInPolygon(x, y float64, rings [][][2]float64) bool {
+	inside := false
+	for _, ring := range rings {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			xi, yi := ring[i][0], ring[i][1]
+			xj, yj := ring[j][0], ring[j][1]
+			if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// polygonIntersectsBox test any edge of polygon intersect the bounding box
+func polygonIntersectsBox(rings [][][2]float64, minX, minY, maxX, maxY float64) bool {
+	for _, ring := range rings {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			if segmentIntersectsBox(ring[j][0], ring[j][1], ring[i][0], ring[i][1], minX, minY, maxX, maxY) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// segmentIntersectsBox test segment (x1, y1)-(x2, y2) intersect the bounding box using Liang–Barsky clipping
+func segmentIntersectsBox(x1, y1, x2, y2, minX, minY, maxX, maxY float64) bool {
+	dx := x2 - x1
+	dy := y2 - y1
+	p := [4]float64{-dx, dx, -dy, dy}
+	q := [4]float64{x1 - minX, maxX - x1, y1 - minY, maxY - y1}
+
+	t0, t1 := 0.0, 1.0
+	for i := range p {
+		if p[i] == 0 {
+			// parallel with the box edge
+			if q[i] < 0 {
+				return false
+			}
+			continue
+		}
+
+		t := q[i] / p[i]
+		if p[i] < 0 {
+			if t > t1 {
+				return false
+			}
+			t0 = math.Max(t0, t)
+		} else {
+			if t < t0 {
+				return false
+			}
+			t1 = math.Min(t1, t)
+		}
+	}
+	return true
+}
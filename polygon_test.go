@@ -0,0 +1,87 @@
+package kdbush_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	// square with square hole
+	squareWithHole = [][][2]float64{
+		{{-5.5, -5.5}, {5.5, -5.5}, {5.5, 5.5}, {-5.5, 5.5}, {-5.5, -5.5}},
+		{{-2.5, -2.5}, {2.5, -2.5}, {2.5, 2.5}, {-2.5, 2.5}},
+	}
+	// concave U shape
+	concave = [][][2]float64{
+		{{-8.5, -8.5}, {8.5, -8.5}, {8.5, 8.5}, {5.5, 8.5}, {5.5, -5.5}, {-5.5, -5.5}, {-5.5, 8.5}, {-8.5, 8.5}},
+	}
+	triangle = [][][2]float64{
+		{{0.5, 0.5}, {9.5, 0.5}, {0.5, 9.5}},
+	}
+)
+
+// Test Polygon func with brute force
+func TestPolygon(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	_points := []kdbush.Point{}
+	for i := 0; i < 5_000; i++ {
+		_points = append(_points, &kdbush.SimplePoint{rng.Float64()*30 - 15, rng.Float64()*30 - 15})
+	}
+
+	for _, nodeSize := range []int{kdbush.STANDARD_NODE_SIZE, 4} {
+		for _, data := range [][]kdbush.Point{points, _points} {
+			bush := kdbush.NewBush().BuildIndex(data, nodeSize)
+
+			for _, polygon := range [][][][2]float64{squareWithHole, concave, triangle} {
+				assert.ElementsMatch(t, bush.Polygon(polygon), brutePolygon(data, polygon))
+			}
+
+			multi := bush.MultiPolygon([][][][2]float64{triangle, concave})
+			expected := brutePolygon(data, concave)
+			for _, id := range brutePolygon(data, triangle) {
+				if !contains(expected, id) {
+					expected = append(expected, id)
+				}
+			}
+			assert.ElementsMatch(t, multi, expected)
+		}
+	}
+
+	bush := kdbush.NewBush().BuildIndex(points, 4)
+	assert.Equal(t, len(bush.Polygon(squareWithHole)), 11*11-5*5)
+	assert.Equal(t, bush.Polygon([][][2]float64{{{20, 20}, {30, 20}, {30, 30}}}), []int{})
+	assert.Equal(t, bush.Polygon(nil), []int{})
+	assert.Equal(t, kdbush.NewBush().Polygon(triangle), []int{})
+}
+
+func brutePolygon(points []kdbush.Point, rings [][][2]float64) []int {
+	result := []int{}
+	for id, p := range points {
+		inside := false
+		for _, ring := range rings {
+			for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+				if (ring[i][1] > p.GetY()) != (ring[j][1] > p.GetY()) &&
+					p.GetX() < (ring[j][0]-ring[i][0])*(p.GetY()-ring[i][1])/(ring[j][1]-ring[i][1])+ring[i][0] {
+					inside = !inside
+				}
+			}
+		}
+		if inside {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func contains(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
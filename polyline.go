@@ -0,0 +1,128 @@
+package kdbush
+
+import "math"
+
+// NearPolyline returns all indexes points within [dist] of any segment of polyline [path], each point is returned once
+func (kd *KDBushOf[C, I]) NearPolyline(path [][2]float64, dist float64) []I {
+	result := []I{}
+	kd.NearPolylineFunc(path, dist, func(id I, segment int, offset, dist float64) bool {
+		result = append(result, id)
+		return true
+	})
+	return result
+}
+
+// NearPolylineFunc calls fn for each point within [dist] of any segment of polyline [path], with index of the nearest segment,
+// along-track offset of its projection from start of the path, and distance to the path. If fn returns false, NearPolylineFunc stops the traversal
+func (kd *KDBushOf[C, I]) NearPolylineFunc(path [][2]float64, dist float64, fn func(id I, segment int, offset, dist float64) bool) {
+	if len(path) == 0 || dist < 0 {
+		return
+	}
+
+	q := newPolylineQuery(path, dist)
+	kd.walk(q.classify, q.contains, func(i int, x, y float64) bool {
+		segment, offset, d := q.nearest(x, y)
+		return fn(kd.ids[i], segment, offset, math.Sqrt(d))
+	}, nil)
+}
+
+// polylineQuery helper struct for API NearPolyline
+type polylineQuery struct {
+	path    [][2]float64
+	offsets []float64
+	dist2   float64
+	bbox    [4]float64
+}
+
+// newPolylineQuery return polylineQuery with offset of each vertex and bounding box of the path expanded by dist
+func newPolylineQuery(path [][2]float64, dist float64) *polylineQuery {
+	q := polylineQuery{
+		path:    path,
+		offsets: make([]float64, len(path)),
+		dist2:   dist * dist,
+		bbox:    [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)},
+	}
+
+	for i, p := range path {
+		if i > 0 {
+			q.offsets[i] = q.offsets[i-1] + math.Hypot(p[0]-path[i-1][0], p[1]-path[i-1][1])
+		}
+		q.bbox[0] = math.Min(q.bbox[0], p[0]-dist)
+		q.bbox[1] = math.Min(q.bbox[1], p[1]-dist)
+		q.bbox[2] = math.Max(q.bbox[2], p[0]+dist)
+		q.bbox[3] = math.Max(q.bbox[3], p[1]+dist)
+	}
+
+	return &q
+}
+
+// segments return number of segments, single vertex path is a zero-length segment
+func (q *polylineQuery) segments() int {
+	return max(1, len(q.path)-1)
+}
+
+// segment return start & end of i-th segment
+func (q *polylineQuery) segment(i int) (a, b [2]float64) {
+	return q.path[i], q.path[min(i+1, len(q.path)-1)]
+}
+
+// nearest return index of the nearest segment from a point, along-track offset of its projection and squared distance
+func (q *polylineQuery) nearest(x, y float64) (segment int, offset, dist float64) {
+	dist = math.Inf(1)
+	for i := 0; i < q.segments(); i++ {
+		a, b := q.segment(i)
+		t, d := projectSegment(x, y, a[0], a[1], b[0], b[1])
+		if d < dist {
+			segment = i
+			offset = q.offsets[i] + t*math.Hypot(b[0]-a[0], b[1]-a[1])
+			dist = d
+		}
+	}
+	return segment, offset, dist
+}
+
+// contains return point within distance of the path or not
+func (q *polylineQuery) contains(x, y float64) bool {
+	for i := 0; i < q.segments(); i++ {
+		a, b := q.segment(i)
+		if segmentDist(x, y, a[0], a[1], b[0], b[1]) <= q.dist2 {
+			return true
+		}
+	}
+	return false
+}
+
+// classify return position of bounding box against the corridor, outside or partially since the corridor is not convex
+func (q *polylineQuery) classify(minX, minY, maxX, maxY float64) int {
+	// clip box with bounding box of the corridor, so corners are finite
+	minX = math.Max(minX, q.bbox[0])
+	minY = math.Max(minY, q.bbox[1])
+	maxX = math.Min(maxX, q.bbox[2])
+	maxY = math.Min(maxY, q.bbox[3])
+	if minX > maxX || minY > maxY {
+		return boxOutside
+	}
+
+	for i := 0; i < q.segments(); i++ {
+		a, b := q.segment(i)
+		if segmentBoxDist(a[0], a[1], b[0], b[1], minX, minY, maxX, maxY) <= q.dist2 {
+			return boxPartial
+		}
+	}
+	return boxOutside
+}
+
+// segmentBoxDist calculate squared distance from segment (x1, y1)-(x2, y2) to bounding box, 0 if they intersect
+func segmentBoxDist(x1, y1, x2, y2, minX, minY, maxX, maxY float64) float64 {
+	if segmentIntersectsBox(x1, y1, x2, y2, minX, minY, maxX, maxY) {
+		return 0
+	}
+
+	// otherwise the nearest pair is either an end of segment or a corner of box
+	d := math.Min(boxDist(x1, y1, minX, minY, maxX, maxY), boxDist(x2, y2, minX, minY, maxX, maxY))
+	d = math.Min(d, segmentDist(minX, minY, x1, y1, x2, y2))
+	d = math.Min(d, segmentDist(maxX, minY, x1, y1, x2, y2))
+	d = math.Min(d, segmentDist(maxX, maxY, x1, y1, x2, y2))
+	d = math.Min(d, segmentDist(minX, maxY, x1, y1, x2, y2))
+	return d
+}
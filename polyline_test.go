@@ -0,0 +1,90 @@
+package kdbush_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test NearPolyline against brute force
+func TestNearPolyline(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	_points := []kdbush.Point{}
+	for i := 0; i < 5_000; i++ {
+		_points = append(_points, &kdbush.SimplePoint{rng.Float64() * 100, rng.Float64() * 100})
+	}
+	bush := kdbush.NewBush().BuildIndex(_points, 8)
+
+	for q := 0; q < 20; q++ {
+		path := [][2]float64{}
+		for i := 0; i < 1+rng.Intn(6); i++ {
+			path = append(path, [2]float64{rng.Float64() * 100, rng.Float64() * 100})
+		}
+		dist := rng.Float64() * 5
+
+		expected := []int{}
+		for i, p := range _points {
+			if polylineDist(path, p.GetX(), p.GetY()) <= dist {
+				expected = append(expected, i)
+			}
+		}
+		assert.ElementsMatch(t, bush.NearPolyline(path, dist), expected, "it should be same with brute force")
+
+		bush.NearPolylineFunc(path, dist, func(id int, segment int, offset, d float64) bool {
+			p := _points[id]
+			assert.InDelta(t, d, polylineDist(path, p.GetX(), p.GetY()), 1e-9, "it should report distance to the path")
+			assert.InDelta(t, d, polylineDist(path[segment:segment+min(2, len(path)-segment)], p.GetX(), p.GetY()), 1e-9, "it should report the nearest segment")
+			return true
+		})
+	}
+}
+
+// Test NearPolyline segment & offset on simple points
+func TestNearPolylineMatch(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, 10)
+
+	path := [][2]float64{{-3, 0.5}, {3, 0.5}, {3, 5.5}}
+	type match struct {
+		segment int
+		offset  float64
+		dist    float64
+	}
+	matches := map[int]match{}
+	bush.NearPolylineFunc(path, 0.5, func(id int, segment int, offset, dist float64) bool {
+		matches[id] = match{segment, offset, dist}
+		return true
+	})
+
+	// 2 rows along first segment, 1 column along second segment, corners are shared
+	assert.Equal(t, len(matches), 7*2+7-2)
+	assert.Equal(t, matches[indexOf(-3, 0)], match{0, 0, 0.5})
+	assert.Equal(t, matches[indexOf(1, 1)], match{0, 4, 0.5})
+	assert.Equal(t, matches[indexOf(3, 5)], match{1, 6 + 4.5, 0})
+	assert.NotContains(t, matches, indexOf(4, 2), "it should not be in the corridor")
+
+	assert.Equal(t, bush.NearPolyline(nil, 1), []int{}, "it should be empty without path")
+	assert.ElementsMatch(t, bush.NearPolyline([][2]float64{{0, 0}}, 1), bush.Within(0, 0, 1), "single vertex should be same with Within")
+}
+
+// polylineDist brute force distance from a point to polyline
+func polylineDist(path [][2]float64, x, y float64) float64 {
+	dist := math.Inf(1)
+	for i := range path {
+		a := path[i]
+		b := path[i]
+		if i+1 < len(path) {
+			b = path[i+1]
+		}
+		dx, dy := b[0]-a[0], b[1]-a[1]
+		t := 0.0
+		if dx != 0 || dy != 0 {
+			t = math.Max(0, math.Min(1, ((x-a[0])*dx+(y-a[1])*dy)/(dx*dx+dy*dy)))
+		}
+		dist = math.Min(dist, math.Hypot(x-a[0]-dx*t, y-a[1]-dy*t))
+	}
+	return dist
+}
@@ -0,0 +1,380 @@
+package kdbush
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"unsafe"
+)
+
+// Binary layout, compatible with ArrayBuffer of Javascript KDBush v4
+//
+//	byte 0     magic byte 0xdb
+//	byte 1     4 bits version + 4 bits array type of coords
+//	byte 2-3   uint16 nodeSize
+//	byte 4-7   uint32 number of items
+//	ids        uint16 when number of items < 65536, otherwise uint32
+//	padding    until coords aligned to 8 bytes
+//	coords     2 * number of items of array type
+//
+// All values are little endian
+const (
+	binaryMagic      = 0xdb
+	binaryVersion    = 1
+	binaryHeaderSize = 8
+)
+
+// Array type code, same order with ARRAY_TYPES in Javascript KDBush v4
+const (
+	arrayInt8 = iota
+	arrayUint8
+	arrayUint8Clamped
+	arrayInt16
+	arrayUint16
+	arrayInt32
+	arrayUint32
+	arrayFloat32
+	arrayFloat64
+)
+
+// arrayTypeSizes bytes per element for each array type code
+var arrayTypeSizes = [...]int{1, 1, 1, 2, 2, 4, 4, 4, 8}
+
+var (
+	// ErrNotIndexed returned when serializing KDBush that not indexed yet
+	ErrNotIndexed = errors.New("kdbush: index is not built yet")
+	// ErrInvalidData returned when data does not appear to be in a KDBush format
+	ErrInvalidData = errors.New("kdbush: data does not appear to be in a KDBush format")
+	// ErrUnsupportedVersion returned when data is using different version of KDBush format
+	ErrUnsupportedVersion = errors.New("kdbush: unsupported KDBush format version")
+	// ErrUnsupportedArrayType returned when data is using unknown array type of coords
+	ErrUnsupportedArrayType = errors.New("kdbush: unsupported array type")
+	// ErrHasDeleted returned when serializing KDBush that has deleted points, call [KDBushOf.Finish] to remove them first
+	ErrHasDeleted = errors.New("kdbush: index has deleted points")
+	// ErrIndexTooLarge returned when nodeSize or number of items can't fit in KDBush format
+	ErrIndexTooLarge = errors.New("kdbush: nodeSize or number of items is too large for KDBush format")
+	// ErrIDOutOfRange returned when an id can't fit in KDBush format, uint16 for less than 65536 items, otherwise uint32,
+	// or an id in KDBush format can't fit in index type I on decoding
+	ErrIDOutOfRange = errors.New("kdbush: id is out of range of KDBush format")
+)
+
+// binaryLayout return bytes size of single id, offset of coords and total size of binary form
+func binaryLayout(numItems, arrayType int) (idSize, coordsOffset, size int) {
+	idSize = 2
+	if numItems >= 65536 {
+		idSize = 4
+	}
+	idsByteSize := numItems * idSize
+	coordsOffset = binaryHeaderSize + idsByteSize + (8-idsByteSize%8)%8
+	size = coordsOffset + numItems*2*arrayTypeSizes[arrayType]
+	return
+}
+
+// arrayTypeOf return array type code of coordinate type C
+func arrayTypeOf[C Coord]() int {
+	var c C
+	switch any(c).(type) {
+	case int8:
+		return arrayInt8
+	case uint8:
+		return arrayUint8
+	case int16:
+		return arrayInt16
+	case uint16:
+		return arrayUint16
+	case int32:
+		return arrayInt32
+	case uint32:
+		return arrayUint32
+	case float32:
+		return arrayFloat32
+	default:
+		return arrayFloat64
+	}
+}
+
+// idSizeOf return bytes size of index type I if it has same layout with ids in binary form, otherwise 0
+func idSizeOf[I ID]() int {
+	var id I
+	switch any(id).(type) {
+	case uint16:
+		return 2
+	case uint32:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// fitsID return id can be converted into index type I without truncation
+func fitsID[I ID](id uint64) bool {
+	var v I
+	switch any(v).(type) {
+	case uint16:
+		return id <= math.MaxUint16
+	case uint32:
+		return id <= math.MaxUint32
+	case int32:
+		return id <= math.MaxInt32
+	case int:
+		return id <= math.MaxInt
+	case int64:
+		return id <= math.MaxInt64
+	default:
+		return true
+	}
+}
+
+// littleEndian is the host little endian, then binary form can be used in place
+var littleEndian = func() bool {
+	v := uint16(1)
+	return *(*byte)(unsafe.Pointer(&v)) == 1
+}()
+
+// MarshalBinary implements [encoding.BinaryMarshaler], encode index into Javascript KDBush v4 format.
+// Array type of coords follows C, e.g. float32 coords are encoded as Float32Array
+func (kd *KDBushOf[C, I]) MarshalBinary() ([]byte, error) {
+	if !kd.indexed {
+		return nil, ErrNotIndexed
+	}
+	if kd.deletedCount > 0 {
+		return nil, ErrHasDeleted
+	}
+
+	numItems := len(kd.ids)
+	if kd.nodeSize < 0 || kd.nodeSize > math.MaxUint16 || uint64(numItems) > math.MaxUint32 {
+		return nil, ErrIndexTooLarge
+	}
+
+	arrayType := arrayTypeOf[C]()
+	idSize, coordsOffset, size := binaryLayout(numItems, arrayType)
+
+	// ids are not always positions, e.g. after Add or BuildIndexWithIDs
+	maxID := uint64(math.MaxUint16)
+	if idSize == 4 {
+		maxID = math.MaxUint32
+	}
+	for _, id := range kd.ids {
+		if id < 0 || uint64(id) > maxID {
+			return nil, ErrIDOutOfRange
+		}
+	}
+
+	data := make([]byte, size)
+
+	data[0] = binaryMagic
+	data[1] = binaryVersion<<4 | byte(arrayType)
+	binary.LittleEndian.PutUint16(data[2:], uint16(kd.nodeSize))
+	binary.LittleEndian.PutUint32(data[4:], uint32(numItems))
+
+	for i, id := range kd.ids {
+		offset := binaryHeaderSize + i*idSize
+		if idSize == 2 {
+			binary.LittleEndian.PutUint16(data[offset:], uint16(id))
+		} else {
+			binary.LittleEndian.PutUint32(data[offset:], uint32(id))
+		}
+	}
+
+	elemSize := arrayTypeSizes[arrayType]
+	for i, v := range kd.coords {
+		encodeCoord(data[coordsOffset+i*elemSize:], v)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler], decode index from Javascript KDBush v4 format.
+// Coords with other array type than C will be converted into C
+func (kd *KDBushOf[C, I]) UnmarshalBinary(data []byte) error {
+	_, err := kd.decode(data, false)
+	return err
+}
+
+// decode index from binary form. If [inPlace], ids and coords point straight into data when their layout are same with I & C.
+// Return true if the index refers to data
+func (kd *KDBushOf[C, I]) decode(data []byte, inPlace bool) (bool, error) {
+	if len(data) < binaryHeaderSize {
+		return false, ErrInvalidData
+	}
+
+	numItems, arrayType, err := decodeHeader(data[:binaryHeaderSize])
+	if err != nil {
+		return false, err
+	}
+
+	idSize, coordsOffset, size := binaryLayout(numItems, arrayType)
+	if len(data) < size {
+		return false, ErrInvalidData
+	}
+
+	inPlace = inPlace && littleEndian && numItems > 0
+	idsInPlace := inPlace && idSizeOf[I]() == idSize
+	coordsInPlace := inPlace && arrayTypeOf[C]() == arrayType
+
+	var ids []I
+	nextID := -1 // unknown until needed, see [KDBushOf.Add]
+	if idsInPlace {
+		ids = unsafe.Slice((*I)(unsafe.Pointer(&data[binaryHeaderSize])), numItems)
+	} else {
+		ids = make([]I, numItems)
+		nextID = 0
+		for i := range ids {
+			offset := binaryHeaderSize + i*idSize
+			id := 0
+			if idSize == 2 {
+				id = int(binary.LittleEndian.Uint16(data[offset:]))
+			} else {
+				id = int(binary.LittleEndian.Uint32(data[offset:]))
+			}
+			if !fitsID[I](uint64(id)) {
+				return false, ErrIDOutOfRange
+			}
+			ids[i] = I(id)
+			nextID = max(nextID, id+1)
+		}
+	}
+
+	var coords []C
+	if coordsInPlace {
+		coords = unsafe.Slice((*C)(unsafe.Pointer(&data[coordsOffset])), 2*numItems)
+	} else {
+		coords = make([]C, 2*numItems)
+		elemSize := arrayTypeSizes[arrayType]
+		for i := range coords {
+			coords[i] = C(decodeCoord(data[coordsOffset+i*elemSize:], arrayType))
+		}
+	}
+
+	kd.reset(int(binary.LittleEndian.Uint16(data[2:])))
+	kd.nextID = nextID
+	kd.ids = ids
+	kd.coords = coords
+	kd.indexed = true
+
+	// avoid reading whole mapping on loading
+	if coordsInPlace {
+		kd.bounds = [4]float64{math.Inf(-1), math.Inf(-1), math.Inf(1), math.Inf(1)}
+	} else {
+		kd.computeBounds()
+	}
+
+	return idsInPlace || coordsInPlace, nil
+}
+
+// WriteTo implements [io.WriterTo], write index into w in Javascript KDBush v4 format
+func (kd *KDBushOf[C, I]) WriteTo(w io.Writer) (int64, error) {
+	data, err := kd.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// WriteFile write index into file in Javascript KDBush v4 format, the file can be loaded by [Open]
+func (kd *KDBushOf[C, I]) WriteFile(path string) error {
+	data, err := kd.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ReadFrom implements [io.ReaderFrom], read index from r in Javascript KDBush v4 format.
+// It only reads as many bytes as the index needs
+func (kd *KDBushOf[C, I]) ReadFrom(r io.Reader) (int64, error) {
+	header := make([]byte, binaryHeaderSize)
+	n, err := io.ReadFull(r, header)
+	if err != nil {
+		return int64(n), errUnexpectedEOF(err)
+	}
+
+	numItems, arrayType, err := decodeHeader(header)
+	if err != nil {
+		return int64(n), err
+	}
+
+	_, _, size := binaryLayout(numItems, arrayType)
+	data := make([]byte, size)
+	copy(data, header)
+
+	m, err := io.ReadFull(r, data[binaryHeaderSize:])
+	if err != nil {
+		return int64(n + m), errUnexpectedEOF(err)
+	}
+
+	return int64(n + m), kd.UnmarshalBinary(data)
+}
+
+// decodeHeader validate header and return number of items and array type of coords
+func decodeHeader(header []byte) (numItems, arrayType int, err error) {
+	if header[0] != binaryMagic {
+		return 0, 0, ErrInvalidData
+	}
+	if header[1]>>4 != binaryVersion {
+		return 0, 0, ErrUnsupportedVersion
+	}
+
+	arrayType = int(header[1] & 0x0f)
+	if arrayType >= len(arrayTypeSizes) {
+		return 0, 0, ErrUnsupportedArrayType
+	}
+
+	numItems = int(binary.LittleEndian.Uint32(header[4:]))
+	return numItems, arrayType, nil
+}
+
+// decodeCoord decode single coord value given array type
+func decodeCoord(b []byte, arrayType int) float64 {
+	switch arrayType {
+	case arrayInt8:
+		return float64(int8(b[0]))
+	case arrayUint8, arrayUint8Clamped:
+		return float64(b[0])
+	case arrayInt16:
+		return float64(int16(binary.LittleEndian.Uint16(b)))
+	case arrayUint16:
+		return float64(binary.LittleEndian.Uint16(b))
+	case arrayInt32:
+		return float64(int32(binary.LittleEndian.Uint32(b)))
+	case arrayUint32:
+		return float64(binary.LittleEndian.Uint32(b))
+	case arrayFloat32:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	default:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+}
+
+// encodeCoord encode single coord value based on its type
+func encodeCoord[C Coord](b []byte, v C) {
+	switch v := any(v).(type) {
+	case int8:
+		b[0] = byte(v)
+	case uint8:
+		b[0] = v
+	case int16:
+		binary.LittleEndian.PutUint16(b, uint16(v))
+	case uint16:
+		binary.LittleEndian.PutUint16(b, v)
+	case int32:
+		binary.LittleEndian.PutUint32(b, uint32(v))
+	case uint32:
+		binary.LittleEndian.PutUint32(b, v)
+	case float32:
+		binary.LittleEndian.PutUint32(b, math.Float32bits(v))
+	case float64:
+		binary.LittleEndian.PutUint64(b, math.Float64bits(v))
+	}
+}
+
+// errUnexpectedEOF treat clean EOF in the middle of index as unexpected
+func errUnexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,119 @@
+package kdbush_test
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test binary layout with Javascript KDBush v4
+func TestMarshalBinaryLayout(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex([]kdbush.Point{
+		&kdbush.SimplePoint{1, 2},
+		&kdbush.SimplePoint{3, 4},
+		&kdbush.SimplePoint{5, 6},
+	}, kdbush.STANDARD_NODE_SIZE)
+
+	data, err := bush.MarshalBinary()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{
+		0xdb, 0x18, 0x40, 0x00, 0x03, 0x00, 0x00, 0x00, // header
+		0x00, 0x00, 0x01, 0x00, 0x02, 0x00, // ids
+		0x00, 0x00, // padding
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f, // 1
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40, // 2
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x40, // 3
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x40, // 4
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x14, 0x40, // 5
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x18, 0x40, // 6
+	}, data, "it should be same with Javascript KDBush v4 layout")
+}
+
+// Test MarshalBinary & UnmarshalBinary
+func TestMarshalBinary(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	for _, total := range []int{0, 1_000, 70_000} {
+		_points := []kdbush.Point{}
+		for i := 0; i < total; i++ {
+			_points = append(_points, &kdbush.SimplePoint{rng.Float64()*24.0 + 24.0, rng.Float64()*24.0 + 24.0})
+		}
+		bush := kdbush.NewBush().BuildIndex(_points, 8)
+
+		data, err := bush.MarshalBinary()
+		assert.Nil(t, err)
+
+		loaded := kdbush.NewBush()
+		assert.Nil(t, loaded.UnmarshalBinary(data))
+		assert.Equal(t, loaded.Indexed(), true, "should indexed")
+		assert.Equal(t, loaded.GetNodeSize(), bush.GetNodeSize(), "nodesize should be same")
+		assert.Equal(t, loaded.GetIndexes(), bush.GetIndexes(), "indexes should be same")
+		assert.Equal(t, loaded.GetCoords(), bush.GetCoords(), "coords should be same")
+		assert.Equal(t, loaded.Range(30, 30, 40, 40), bush.Range(30, 30, 40, 40), "range result should be same")
+		assert.Equal(t, loaded.Within(30, 30, 5), bush.Within(30, 30, 5), "within result should be same")
+	}
+
+	_, err := kdbush.NewBush().MarshalBinary()
+	assert.Equal(t, err, kdbush.ErrNotIndexed)
+
+	_, err = kdbush.NewBush().BuildIndex(points, 1<<16).MarshalBinary()
+	assert.Equal(t, err, kdbush.ErrIndexTooLarge)
+}
+
+// Test UnmarshalBinary with other array type & invalid data
+func TestUnmarshalBinary(t *testing.T) {
+	// Int16Array coords
+	bush := kdbush.NewBush()
+	assert.Nil(t, bush.UnmarshalBinary([]byte{
+		0xdb, 0x13, 0x40, 0x00, 0x02, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xff, 0xff, 0x02, 0x00, 0x03, 0x00, 0xfc, 0xff,
+	}))
+	assert.Equal(t, bush.GetIndexes(), []int{0, 1})
+	assert.Equal(t, bush.GetCoords(), []float64{-1, 2, 3, -4})
+
+	assert.Equal(t, kdbush.NewBush().UnmarshalBinary([]byte{0xdb}), kdbush.ErrInvalidData)
+	assert.Equal(t, kdbush.NewBush().UnmarshalBinary([]byte{0xaa, 0x18, 0, 0, 0, 0, 0, 0}), kdbush.ErrInvalidData)
+	assert.Equal(t, kdbush.NewBush().UnmarshalBinary([]byte{0xdb, 0x28, 0, 0, 0, 0, 0, 0}), kdbush.ErrUnsupportedVersion)
+	assert.Equal(t, kdbush.NewBush().UnmarshalBinary([]byte{0xdb, 0x19, 0, 0, 0, 0, 0, 0}), kdbush.ErrUnsupportedArrayType)
+	assert.Equal(t, kdbush.NewBush().UnmarshalBinary([]byte{0xdb, 0x18, 0, 0, 1, 0, 0, 0}), kdbush.ErrInvalidData)
+
+	// uint32 ids can't fit in uint16 index type
+	many := make([]kdbush.Point, 70000)
+	for i := range many {
+		many[i] = &kdbush.SimplePoint{X: float64(i), Y: float64(i)}
+	}
+	data, err := kdbush.NewBushOf[float64, uint32]().BuildIndex(many, 64).MarshalBinary()
+	assert.Nil(t, err)
+	assert.Equal(t, kdbush.NewBushOf[float64, uint16]().UnmarshalBinary(data), kdbush.ErrIDOutOfRange)
+	assert.Nil(t, kdbush.NewBushOf[float64, int32]().UnmarshalBinary(data))
+}
+
+// Test WriteTo & ReadFrom
+func TestWriteToReadFrom(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, 4)
+
+	buf := bytes.Buffer{}
+	n, err := bush.WriteTo(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	// trailing data should not be consumed
+	buf.WriteString("trailing")
+
+	loaded := kdbush.NewBush()
+	m, err := loaded.ReadFrom(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, n, m)
+	assert.Equal(t, loaded.GetIndexes(), bush.GetIndexes(), "indexes should be same")
+	assert.Equal(t, loaded.GetCoords(), bush.GetCoords(), "coords should be same")
+	assert.Equal(t, buf.String(), "trailing")
+
+	data, _ := bush.MarshalBinary()
+	_, err = kdbush.NewBush().ReadFrom(bytes.NewReader(data[:len(data)-1]))
+	assert.Equal(t, err, io.ErrUnexpectedEOF)
+}
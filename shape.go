@@ -0,0 +1,259 @@
+package kdbush
+
+import "math"
+
+// Annulus returns all indexes points within ring between [rInner] and [rOuter] radius of given single [Point].
+// It returns empty result when rOuter is negative or less than rInner
+func (kd *KDBushOf[C, I]) Annulus(qx, qy float64, rInner, rOuter float64) []I {
+	result := []I{}
+	if rOuter < 0 || rOuter < rInner {
+		return result
+	}
+
+	r2Inner := rInner * rInner
+	r2Outer := rOuter * rOuter
+	if rInner < 0 {
+		r2Inner = 0
+	}
+
+	kd.walk(func(minX, minY, maxX, maxY float64) int {
+		near := boxDist(qx, qy, minX, minY, maxX, maxY)
+		far := farthestBoxDist(qx, qy, minX, minY, maxX, maxY)
+		if near > r2Outer || far < r2Inner {
+			// beyond outer circle or entirely inside the hole
+			return boxOutside
+		}
+		if near >= r2Inner && far <= r2Outer {
+			return boxInside
+		}
+		return boxPartial
+	}, func(x, y float64) bool {
+		d := sqrtDist(x, y, qx, qy)
+		return d >= r2Inner && d <= r2Outer
+	}, func(i int, x, y float64) bool {
+		result = append(result, kd.ids[i])
+		return true
+	}, nil)
+
+	return result
+}
+
+// Ellipse returns all indexes points inside ellipse centered at [cx], [cy] with semi-axis [a] along x and [b] along y before rotated.
+// [rotation] is counter-clockwise angle of the ellipse in radians
+func (kd *KDBushOf[C, I]) Ellipse(cx, cy, a, b, rotation float64) []I {
+	result := []I{}
+	if !(a > 0 && b > 0) {
+		return result
+	}
+
+	q := newEllipseQuery(cx, cy, a, b, rotation)
+	kd.walk(q.classify, q.contains, func(i int, x, y float64) bool {
+		result = append(result, kd.ids[i])
+		return true
+	}, nil)
+
+	return result
+}
+
+// OrientedBox returns all indexes points inside rectangle centered at [cx], [cy] with [halfWidth] along x and [halfHeight] along y before rotated.
+// [rotation] is counter-clockwise angle of the rectangle in radians
+func (kd *KDBushOf[C, I]) OrientedBox(cx, cy, halfWidth, halfHeight, rotation float64) []I {
+	result := []I{}
+	if !(halfWidth >= 0 && halfHeight >= 0) {
+		return result
+	}
+
+	q := newOrientedBoxQuery(cx, cy, halfWidth, halfHeight, rotation)
+	kd.walk(q.classify, q.contains, func(i int, x, y float64) bool {
+		result = append(result, kd.ids[i])
+		return true
+	}, nil)
+
+	return result
+}
+
+// rotatedQuery shared frame of rotated shapes, point is transformed into local axis of the shape
+type rotatedQuery struct {
+	cx   float64
+	cy   float64
+	cos  float64
+	sin  float64
+	bbox [4]float64
+}
+
+// local transform point into local axis of the shape, centered at origin
+func (q *rotatedQuery) local(x, y float64) (u, v float64) {
+	dx := x - q.cx
+	dy := y - q.cy
+	return dx*q.cos + dy*q.sin, -dx*q.sin + dy*q.cos
+}
+
+// corners clip bounding box with bounding box of the shape, there is no part of the shape outside it.
+// Return corners of clipped box in local axis, whether the box is clipped, and false if nothing left
+func (q *rotatedQuery) corners(minX, minY, maxX, maxY float64) (c [4][2]float64, clipped bool, ok bool) {
+	cMinX := math.Max(minX, q.bbox[0])
+	cMinY := math.Max(minY, q.bbox[1])
+	cMaxX := math.Min(maxX, q.bbox[2])
+	cMaxY := math.Min(maxY, q.bbox[3])
+	if cMinX > cMaxX || cMinY > cMaxY {
+		return c, true, false
+	}
+
+	c[0][0], c[0][1] = q.local(cMinX, cMinY)
+	c[1][0], c[1][1] = q.local(cMaxX, cMinY)
+	c[2][0], c[2][1] = q.local(cMaxX, cMaxY)
+	c[3][0], c[3][1] = q.local(cMinX, cMaxY)
+	clipped = cMinX != minX || cMinY != minY || cMaxX != maxX || cMaxY != maxY
+	return c, clipped, true
+}
+
+// ellipseQuery helper struct for API Ellipse, local axis is scaled so the ellipse become unit circle
+type ellipseQuery struct {
+	rotatedQuery
+	a float64
+	b float64
+}
+
+// newEllipseQuery return ellipseQuery with bounding box of the ellipse
+func newEllipseQuery(cx, cy, a, b, rotation float64) *ellipseQuery {
+	sin, cos := math.Sincos(rotation)
+	hx := math.Hypot(a*cos, b*sin)
+	hy := math.Hypot(a*sin, b*cos)
+	return &ellipseQuery{
+		rotatedQuery: rotatedQuery{cx, cy, cos, sin, [4]float64{cx - hx, cy - hy, cx + hx, cy + hy}},
+		a:            a,
+		b:            b,
+	}
+}
+
+// contains return point inside the ellipse or not
+func (q *ellipseQuery) contains(x, y float64) bool {
+	u, v := q.local(x, y)
+	u /= q.a
+	v /= q.b
+	return u*u+v*v <= 1
+}
+
+// classify return position of bounding box against the ellipse
+func (q *ellipseQuery) classify(minX, minY, maxX, maxY float64) int {
+	c, clipped, ok := q.corners(minX, minY, maxX, maxY)
+	if !ok {
+		return boxOutside
+	}
+
+	// box become parallelogram in unit circle space
+	inside := true
+	for i := range c {
+		c[i][0] /= q.a
+		c[i][1] /= q.b
+		if c[i][0]*c[i][0]+c[i][1]*c[i][1] > 1 {
+			inside = false
+		}
+	}
+	if inside {
+		// ellipse is convex, so the box is inside when all corners are inside
+		if clipped {
+			return boxPartial
+		}
+		return boxInside
+	}
+
+	if quadContainsOrigin(c) {
+		return boxPartial
+	}
+	for i, j := 0, len(c)-1; i < len(c); j, i = i, i+1 {
+		if segmentDist(0, 0, c[j][0], c[j][1], c[i][0], c[i][1]) <= 1 {
+			return boxPartial
+		}
+	}
+	return boxOutside
+}
+
+// orientedBoxQuery helper struct for API OrientedBox
+type orientedBoxQuery struct {
+	rotatedQuery
+	halfWidth  float64
+	halfHeight float64
+}
+
+// newOrientedBoxQuery return orientedBoxQuery with bounding box of the rectangle
+func newOrientedBoxQuery(cx, cy, halfWidth, halfHeight, rotation float64) *orientedBoxQuery {
+	sin, cos := math.Sincos(rotation)
+	hx := math.Abs(halfWidth*cos) + math.Abs(halfHeight*sin)
+	hy := math.Abs(halfWidth*sin) + math.Abs(halfHeight*cos)
+	return &orientedBoxQuery{
+		rotatedQuery: rotatedQuery{cx, cy, cos, sin, [4]float64{cx - hx, cy - hy, cx + hx, cy + hy}},
+		halfWidth:    halfWidth,
+		halfHeight:   halfHeight,
+	}
+}
+
+// contains return point inside the rectangle or not
+func (q *orientedBoxQuery) contains(x, y float64) bool {
+	u, v := q.local(x, y)
+	return math.Abs(u) <= q.halfWidth && math.Abs(v) <= q.halfHeight
+}
+
+// classify return position of bounding box against the rectangle using separating axis test,
+// axis of the box is tested by clipping and axis of the rectangle by projecting the corners
+func (q *orientedBoxQuery) classify(minX, minY, maxX, maxY float64) int {
+	c, clipped, ok := q.corners(minX, minY, maxX, maxY)
+	if !ok {
+		return boxOutside
+	}
+
+	minU, minV := math.Inf(1), math.Inf(1)
+	maxU, maxV := math.Inf(-1), math.Inf(-1)
+	for _, p := range c {
+		minU = math.Min(minU, p[0])
+		minV = math.Min(minV, p[1])
+		maxU = math.Max(maxU, p[0])
+		maxV = math.Max(maxV, p[1])
+	}
+
+	if minU > q.halfWidth || maxU < -q.halfWidth || minV > q.halfHeight || maxV < -q.halfHeight {
+		return boxOutside
+	}
+	if !clipped && minU >= -q.halfWidth && maxU <= q.halfWidth && minV >= -q.halfHeight && maxV <= q.halfHeight {
+		return boxInside
+	}
+	return boxPartial
+}
+
+// farthestBoxDist calculate squared distance from a point to the farthest corner of bounding box
+func farthestBoxDist(x, y, minX, minY, maxX, maxY float64) float64 {
+	dx := max(x-minX, maxX-x)
+	dy := max(y-minY, maxY-y)
+	return dx*dx + dy*dy
+}
+
+// segmentDist calculate squared distance from a point to segment (x1, y1)-(x2, y2)
+func segmentDist(x, y, x1, y1, x2, y2 float64) float64 {
+	_, d := projectSegment(x, y, x1, y1, x2, y2)
+	return d
+}
+
+// projectSegment project a point into segment (x1, y1)-(x2, y2).
+// Return position of the nearest point in the segment from 0 at (x1, y1) to 1 at (x2, y2), and its squared distance
+func projectSegment(x, y, x1, y1, x2, y2 float64) (t, dist float64) {
+	dx := x2 - x1
+	dy := y2 - y1
+	if dx != 0 || dy != 0 {
+		t = math.Max(0, math.Min(1, ((x-x1)*dx+(y-y1)*dy)/(dx*dx+dy*dy)))
+	}
+	return t, sqrtDist(x, y, x1+dx*t, y1+dy*t)
+}
+
+// quadContainsOrigin test origin inside convex quadrilateral, corners can be in either winding order
+func quadContainsOrigin(c [4][2]float64) bool {
+	pos, neg := false, false
+	for i, j := 0, len(c)-1; i < len(c); j, i = i, i+1 {
+		cross := c[j][0]*c[i][1] - c[j][1]*c[i][0]
+		if cross > 0 {
+			pos = true
+		} else if cross < 0 {
+			neg = true
+		}
+	}
+	return !(pos && neg)
+}
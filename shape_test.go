@@ -0,0 +1,80 @@
+package kdbush_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test Annulus, Ellipse & OrientedBox against brute force
+func TestShape(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	_points := []kdbush.Point{}
+	for i := 0; i < 5_000; i++ {
+		_points = append(_points, &kdbush.SimplePoint{rng.Float64() * 100, rng.Float64() * 100})
+	}
+
+	brute := func(contains func(x, y float64) bool) []int {
+		result := []int{}
+		for i, p := range _points {
+			if contains(p.GetX(), p.GetY()) {
+				result = append(result, i)
+			}
+		}
+		return result
+	}
+
+	for _, nodeSize := range []int{kdbush.STANDARD_NODE_SIZE, 4} {
+		bush := kdbush.NewBush().BuildIndex(_points, nodeSize)
+
+		for q := 0; q < 30; q++ {
+			cx, cy := rng.Float64()*100, rng.Float64()*100
+			rotation := rng.Float64() * 2 * math.Pi
+			a, b := rng.Float64()*30, rng.Float64()*30
+			rInner, rOuter := math.Min(a, b), math.Max(a, b)
+			sin, cos := math.Sincos(rotation)
+			local := func(x, y float64) (float64, float64) {
+				dx, dy := x-cx, y-cy
+				return dx*cos + dy*sin, -dx*sin + dy*cos
+			}
+
+			assert.ElementsMatch(t, bush.Annulus(cx, cy, rInner, rOuter), brute(func(x, y float64) bool {
+				d := math.Hypot(x-cx, y-cy)
+				return d >= rInner && d <= rOuter
+			}), "annulus should be same with brute force")
+
+			assert.ElementsMatch(t, bush.Ellipse(cx, cy, a, b, rotation), brute(func(x, y float64) bool {
+				u, v := local(x, y)
+				return (u/a)*(u/a)+(v/b)*(v/b) <= 1
+			}), "ellipse should be same with brute force")
+
+			assert.ElementsMatch(t, bush.OrientedBox(cx, cy, a, b, rotation), brute(func(x, y float64) bool {
+				u, v := local(x, y)
+				return math.Abs(u) <= a && math.Abs(v) <= b
+			}), "oriented box should be same with brute force")
+		}
+	}
+}
+
+// Test shapes on simple points
+func TestShapeSimple(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, 10)
+
+	assert.ElementsMatch(t, bush.Annulus(0, 0, 0, 1), bush.Within(0, 0, 1), "annulus without hole should be same with Within")
+	assert.ElementsMatch(t, bush.Annulus(0, 0, 1.5, 1.5), []int{})
+	assert.ElementsMatch(t, bush.Annulus(0, 0, 1, 1), []int{indexOf(-1, 0), indexOf(1, 0), indexOf(0, -1), indexOf(0, 1)})
+	assert.ElementsMatch(t, bush.Annulus(0, 0, 0, -5), []int{}, "it should be empty with negative outer radius")
+	assert.ElementsMatch(t, bush.Annulus(0, 0, -10, -5), []int{}, "it should be empty with negative outer radius")
+	assert.ElementsMatch(t, bush.Annulus(0, 0, 2, 1), []int{}, "it should be empty with outer radius less than inner radius")
+
+	assert.ElementsMatch(t, bush.Ellipse(0, 0, 2, 1, 0), []int{indexOf(-2, 0), indexOf(-1, 0), indexOf(0, 0), indexOf(1, 0), indexOf(2, 0), indexOf(0, -1), indexOf(0, 1)})
+	assert.ElementsMatch(t, bush.Ellipse(0, 0, 2, 1, math.Pi/2), []int{indexOf(0, -2), indexOf(0, -1), indexOf(0, 0), indexOf(0, 1), indexOf(0, 2), indexOf(-1, 0), indexOf(1, 0)})
+	assert.ElementsMatch(t, bush.Ellipse(0, 0, 0, 1, 0), []int{}, "it should be empty with zero semi-axis")
+
+	assert.ElementsMatch(t, bush.OrientedBox(0, 0, 2.1, 0.1, 0), bush.Range(-2.1, -0.1, 2.1, 0.1), "unrotated box should be same with Range")
+	assert.ElementsMatch(t, bush.OrientedBox(0, 0, math.Sqrt2+0.01, 0.01, math.Pi/4), []int{indexOf(-1, -1), indexOf(0, 0), indexOf(1, 1)})
+}
@@ -0,0 +1,49 @@
+package supercluster
+
+import "math"
+
+// helper for supercluster, spherical mercator projection into [0..1]
+
+// lngX project longitude into x
+func lngX(lng float64) float64 {
+	return lng/360 + 0.5
+}
+
+// latY project latitude into y
+func latY(lat float64) float64 {
+	sin := math.Sin(lat * math.Pi / 180)
+	y := 0.5 - 0.25*math.Log((1+sin)/(1-sin))/math.Pi
+	return math.Max(0, math.Min(1, y))
+}
+
+// xLng unproject x into longitude
+func xLng(x float64) float64 {
+	return (x - 0.5) * 360
+}
+
+// yLat unproject y into latitude
+func yLat(y float64) float64 {
+	y2 := (180 - y*360) * math.Pi / 180
+	return 360*math.Atan(math.Exp(y2))/math.Pi - 90
+}
+
+// fround round into float32 precision, same with Math.fround in Javascript
+func fround(v float64) float64 {
+	return float64(float32(v))
+}
+
+// min
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// max
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,402 @@
+// Package supercluster implements hierarchical clustering of map markers per zoom level, a port of Javascript mapbox/supercluster v8 built on KDBush
+package supercluster
+
+import (
+	"errors"
+	"math"
+
+	"github.com/raditzlawliet/kdbush"
+)
+
+// ErrClusterNotFound returned when cluster id does not exist
+var ErrClusterNotFound = errors.New("supercluster: no cluster with the specified id")
+
+// Options of [Supercluster], start from [DefaultOptions] and change what needed
+type Options[P any] struct {
+	// MinZoom minimum zoom level to generate clusters on
+	MinZoom int
+	// MaxZoom maximum zoom level to cluster the points on
+	MaxZoom int
+	// MinPoints minimum number of points to form a cluster
+	MinPoints int
+	// Radius cluster radius in pixels
+	Radius float64
+	// Extent tile extent, radius is calculated relative to it
+	Extent float64
+	// NodeSize size of the KDBush leaf node
+	NodeSize int
+
+	// Map return properties of input point at given index, it is aggregated into cluster properties by Reduce
+	Map func(index int) P
+	// Reduce merge props into accumulated properties of cluster and return the result.
+	// It must not modify its arguments in place, since they may belong to another cluster or point
+	Reduce func(acc, props P) P
+}
+
+// DefaultOptions return options with same default values of Javascript mapbox/supercluster
+func DefaultOptions[P any]() Options[P] {
+	return Options[P]{
+		MinZoom:   0,
+		MaxZoom:   16,
+		MinPoints: 2,
+		Radius:    40,
+		Extent:    512,
+		NodeSize:  64,
+	}
+}
+
+// Cluster either a cluster or a single input point, returned by queries
+type Cluster[P any] struct {
+	// ID cluster id if [Cluster.Cluster], otherwise index of input point
+	ID int
+	// Cluster true for cluster, false for single input point
+	Cluster bool
+	// Lng & Lat location of the point, or weighted center of points in the cluster
+	Lng float64
+	Lat float64
+	// NumPoints number of input points in the cluster, 1 for single point
+	NumPoints int
+	// Properties reduced properties of cluster, or mapped properties of single point. Zero value without [Options.Map]
+	Properties P
+}
+
+// Supercluster index of clusters for each zoom level
+type Supercluster[P any] struct {
+	options Options[P]
+	points  []kdbush.Point
+	levels  []level[P]
+}
+
+// level KDBush index of clusters & points at single zoom level, id in the index is position in data
+type level[P any] struct {
+	tree *kdbush.KDBushOf[float32, int]
+	data []node[P]
+}
+
+// node a cluster or single point at some zoom level
+type node[P any] struct {
+	// x & y projected into [0..1]
+	x float64
+	y float64
+	// zoom last zoom the node was processed at, +Inf if not yet
+	zoom float64
+	// id cluster id, or index of input point
+	id int
+	// parentID id of the cluster the node belongs to at lower zoom, -1 if none
+	parentID  int
+	numPoints int
+	props     P
+}
+
+// New return a new pointer of [Supercluster] with given options, call [Supercluster.Load] to build the clusters
+func New[P any](options Options[P]) *Supercluster[P] {
+	return &Supercluster[P]{options: options}
+}
+
+// Load build clusters of points for every zoom level, [kdbush.Point] X & Y are longitude & latitude
+func (sc *Supercluster[P]) Load(points []kdbush.Point) *Supercluster[P] {
+	minZoom := sc.options.MinZoom
+	maxZoom := sc.options.MaxZoom
+
+	sc.points = points
+	sc.levels = make([]level[P], maxZoom+2)
+
+	// generate a cluster object for each point and index input points into a KD-tree
+	data := make([]node[P], 0, len(points))
+	for i, p := range points {
+		data = append(data, node[P]{
+			x:         fround(lngX(p.GetX())),
+			y:         fround(latY(p.GetY())),
+			zoom:      math.Inf(1),
+			id:        i,
+			parentID:  -1,
+			numPoints: 1,
+		})
+	}
+	sc.levels[maxZoom+1] = sc.createLevel(data)
+
+	// cluster points on max zoom, then cluster the results on previous zoom, etc.;
+	// results in a cluster hierarchy across zoom levels
+	for z := maxZoom; z >= minZoom; z-- {
+		sc.levels[z] = sc.createLevel(sc.cluster(&sc.levels[z+1], z))
+	}
+
+	return sc
+}
+
+// GetClusters returns clusters & points at given zoom level inside bounding box [west, south, east, north] in degrees.
+// Bounding box across the antimeridian is supported
+func (sc *Supercluster[P]) GetClusters(bbox [4]float64, zoom int) []Cluster[P] {
+	minLng := math.Mod(math.Mod(bbox[0]+180, 360)+360, 360) - 180
+	minLat := math.Max(-90, math.Min(90, bbox[1]))
+	maxLng := 180.0
+	if bbox[2] != 180 {
+		maxLng = math.Mod(math.Mod(bbox[2]+180, 360)+360, 360) - 180
+	}
+	maxLat := math.Max(-90, math.Min(90, bbox[3]))
+
+	if bbox[2]-bbox[0] >= 360 {
+		minLng = -180
+		maxLng = 180
+	} else if minLng > maxLng {
+		easternHem := sc.GetClusters([4]float64{minLng, minLat, 180, maxLat}, zoom)
+		westernHem := sc.GetClusters([4]float64{-180, minLat, maxLng, maxLat}, zoom)
+		return append(easternHem, westernHem...)
+	}
+
+	result := []Cluster[P]{}
+	if len(sc.levels) == 0 {
+		return result
+	}
+
+	l := &sc.levels[sc.limitZoom(zoom)]
+	l.tree.RangeFunc(lngX(minLng), latY(maxLat), lngX(maxLng), latY(minLat), func(id int, x, y float64) bool {
+		result = append(result, sc.toCluster(&l.data[id]))
+		return true
+	})
+	return result
+}
+
+// GetChildren returns children of cluster on the next zoom level, either clusters or points
+func (sc *Supercluster[P]) GetChildren(clusterID int) ([]Cluster[P], error) {
+	originID := sc.originID(clusterID)
+	originZoom := sc.originZoom(clusterID)
+	if clusterID < len(sc.points) || originZoom >= len(sc.levels) || sc.levels[originZoom].tree == nil {
+		return nil, ErrClusterNotFound
+	}
+
+	l := &sc.levels[originZoom]
+	if originID >= len(l.data) {
+		return nil, ErrClusterNotFound
+	}
+
+	r := sc.options.Radius / (sc.options.Extent * math.Pow(2, float64(originZoom-1)))
+	origin := &l.data[originID]
+
+	children := []Cluster[P]{}
+	l.tree.WithinFunc(origin.x, origin.y, r, func(id int, x, y float64) bool {
+		if l.data[id].parentID == clusterID {
+			children = append(children, sc.toCluster(&l.data[id]))
+		}
+		return true
+	})
+
+	if len(children) == 0 {
+		return nil, ErrClusterNotFound
+	}
+	return children, nil
+}
+
+// GetLeaves returns input points of cluster, with pagination. Use -1 on [limit] for all points, 0 returns no point
+func (sc *Supercluster[P]) GetLeaves(clusterID int, limit, offset int) ([]Cluster[P], error) {
+	leaves := []Cluster[P]{}
+	if limit == 0 {
+		// appendLeaves only stops after a point is added
+		if _, err := sc.GetChildren(clusterID); err != nil {
+			return nil, err
+		}
+		return leaves, nil
+	}
+	if _, err := sc.appendLeaves(&leaves, clusterID, limit, offset, 0); err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+// GetClusterExpansionZoom returns zoom level on which the cluster expands into several children, useful for "click to zoom" feature
+func (sc *Supercluster[P]) GetClusterExpansionZoom(clusterID int) (int, error) {
+	expansionZoom := sc.originZoom(clusterID) - 1
+	for expansionZoom <= sc.options.MaxZoom {
+		children, err := sc.GetChildren(clusterID)
+		if err != nil {
+			return 0, err
+		}
+		expansionZoom++
+		if len(children) != 1 || !children[0].Cluster {
+			break
+		}
+		clusterID = children[0].ID
+	}
+	return expansionZoom, nil
+}
+
+// appendLeaves append input points of cluster into result after skipping [offset] points, return number of skipped points so far
+func (sc *Supercluster[P]) appendLeaves(result *[]Cluster[P], clusterID int, limit, offset, skipped int) (int, error) {
+	children, err := sc.GetChildren(clusterID)
+	if err != nil {
+		return skipped, err
+	}
+
+	for _, child := range children {
+		if child.Cluster {
+			if skipped+child.NumPoints <= offset {
+				// skip a whole cluster
+				skipped += child.NumPoints
+			} else if skipped, err = sc.appendLeaves(result, child.ID, limit, offset, skipped); err != nil {
+				return skipped, err
+			}
+		} else if skipped < offset {
+			// skip a single point
+			skipped++
+		} else {
+			// add a single point
+			*result = append(*result, child)
+		}
+		if len(*result) == limit {
+			break
+		}
+	}
+
+	return skipped, nil
+}
+
+// createLevel index nodes into KDBush
+func (sc *Supercluster[P]) createLevel(data []node[P]) level[P] {
+	tree := kdbush.NewBushOfWithCapacity[float32, int](len(data), sc.options.NodeSize)
+	for i := range data {
+		tree.Add(data[i].x, data[i].y)
+	}
+	tree.Finish()
+	return level[P]{tree, data}
+}
+
+// cluster merge nodes of next zoom level within radius into clusters of given zoom level
+func (sc *Supercluster[P]) cluster(next *level[P], zoom int) []node[P] {
+	r := sc.options.Radius / (sc.options.Extent * math.Pow(2, float64(zoom)))
+	z := float64(zoom)
+	data := next.data
+	result := []node[P]{}
+	neighborIDs := []int{}
+
+	// loop through each point
+	for i := range data {
+		// if we've already visited the point at this zoom level, skip it
+		if data[i].zoom <= z {
+			continue
+		}
+		data[i].zoom = z
+
+		// find all nearby points
+		p := &data[i]
+		neighborIDs = next.tree.WithinAppend(neighborIDs[:0], p.x, p.y, r)
+
+		numPointsOrigin := p.numPoints
+		numPoints := numPointsOrigin
+
+		// count the number of points in a potential cluster
+		for _, k := range neighborIDs {
+			// filter out neighbors that are already processed
+			if data[k].zoom > z {
+				numPoints += data[k].numPoints
+			}
+		}
+
+		// if there were neighbors to merge, and there are enough points to form a cluster
+		if numPoints > numPointsOrigin && numPoints >= sc.options.MinPoints {
+			wx := p.x * float64(numPointsOrigin)
+			wy := p.y * float64(numPointsOrigin)
+
+			// encode both zoom and point index on which the cluster originated -- offset by total length of features
+			id := (i << 5) + (zoom + 1) + len(sc.points)
+
+			var props P
+			if sc.options.Reduce != nil {
+				props = sc.props(p)
+			}
+
+			for _, k := range neighborIDs {
+				b := &data[k]
+				if b.zoom <= z {
+					continue
+				}
+				b.zoom = z // save the zoom (so it doesn't get processed twice)
+
+				wx += b.x * float64(b.numPoints) // accumulate coordinates for calculating weighted center
+				wy += b.y * float64(b.numPoints)
+
+				b.parentID = id
+
+				if sc.options.Reduce != nil {
+					props = sc.options.Reduce(props, sc.props(b))
+				}
+			}
+
+			p.parentID = id
+			result = append(result, node[P]{
+				x:         wx / float64(numPoints),
+				y:         wy / float64(numPoints),
+				zoom:      math.Inf(1),
+				id:        id,
+				parentID:  -1,
+				numPoints: numPoints,
+				props:     props,
+			})
+		} else {
+			// left points as unclustered
+			result = append(result, *p)
+
+			if numPoints > 1 {
+				for _, k := range neighborIDs {
+					b := &data[k]
+					if b.zoom <= z {
+						continue
+					}
+					b.zoom = z
+					result = append(result, *b)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// props return properties of node, mapped from input point or reduced of cluster
+func (sc *Supercluster[P]) props(n *node[P]) P {
+	if n.numPoints > 1 {
+		return n.props
+	}
+	if sc.options.Map != nil {
+		return sc.options.Map(n.id)
+	}
+	var props P
+	return props
+}
+
+// toCluster convert node into [Cluster], single point keeps its original location
+func (sc *Supercluster[P]) toCluster(n *node[P]) Cluster[P] {
+	if n.numPoints > 1 {
+		return Cluster[P]{
+			ID:         n.id,
+			Cluster:    true,
+			Lng:        xLng(n.x),
+			Lat:        yLat(n.y),
+			NumPoints:  n.numPoints,
+			Properties: n.props,
+		}
+	}
+
+	p := sc.points[n.id]
+	return Cluster[P]{
+		ID:         n.id,
+		Lng:        p.GetX(),
+		Lat:        p.GetY(),
+		NumPoints:  1,
+		Properties: sc.props(n),
+	}
+}
+
+// limitZoom clamp zoom into available zoom levels
+func (sc *Supercluster[P]) limitZoom(zoom int) int {
+	return max(sc.options.MinZoom, min(zoom, sc.options.MaxZoom+1))
+}
+
+// originID index of node in zoom level the cluster originated from
+func (sc *Supercluster[P]) originID(clusterID int) int {
+	return (clusterID - len(sc.points)) >> 5
+}
+
+// originZoom zoom level the cluster originated from
+func (sc *Supercluster[P]) originZoom(clusterID int) int {
+	return (clusterID - len(sc.points)) % 32
+}
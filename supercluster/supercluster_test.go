@@ -0,0 +1,197 @@
+package supercluster_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/raditzlawliet/kdbush/geo"
+	"github.com/raditzlawliet/kdbush/supercluster"
+	"github.com/stretchr/testify/assert"
+)
+
+var world = [4]float64{-180, -85, 180, 85}
+
+// randomPoints return random locations with some dense areas
+func randomPoints(n int) []kdbush.Point {
+	var rng = rand.New(rand.NewSource(1))
+
+	points := []kdbush.Point{}
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			points = append(points, &geo.MarkerPoint{Lng: rng.Float64()*360 - 180, Lat: rng.Float64()*160 - 80})
+		} else {
+			points = append(points, &geo.MarkerPoint{Lng: 112.75 + rng.NormFloat64(), Lat: -7.26 + rng.NormFloat64()})
+		}
+	}
+	return points
+}
+
+// Test clusters of every zoom level cover all points
+func TestGetClusters(t *testing.T) {
+	points := randomPoints(2_000)
+	sc := supercluster.New(supercluster.DefaultOptions[int]()).Load(points)
+
+	prev := 0
+	for z := 0; z <= 17; z++ {
+		clusters := sc.GetClusters(world, z)
+
+		total := 0
+		for _, c := range clusters {
+			total += c.NumPoints
+			if !c.Cluster {
+				assert.Equal(t, c.NumPoints, 1)
+				assert.Equal(t, c.Lng, points[c.ID].GetX(), "single point should keep its location")
+			}
+		}
+		assert.Equal(t, total, len(points), "clusters should cover all points")
+		assert.GreaterOrEqual(t, len(clusters), prev, "higher zoom should have more clusters")
+		prev = len(clusters)
+	}
+
+	assert.Equal(t, len(sc.GetClusters(world, 17)), len(points), "all points are unclustered above max zoom")
+	assert.Equal(t, sc.GetClusters(world, 100), sc.GetClusters(world, 17), "zoom should be limited")
+	assert.Less(t, len(sc.GetClusters(world, 0)), 100)
+}
+
+// Test GetClusters with bounding box across the antimeridian
+func TestGetClustersAntimeridian(t *testing.T) {
+	points := []kdbush.Point{
+		&geo.MarkerPoint{Lng: -178.989, Lat: 0},
+		&geo.MarkerPoint{Lng: -178.990, Lat: 0},
+		&geo.MarkerPoint{Lng: -178.991, Lat: 0},
+		&geo.MarkerPoint{Lng: -178.992, Lat: 0},
+		&geo.MarkerPoint{Lng: 179.989, Lat: 0},
+		&geo.MarkerPoint{Lng: 0, Lat: 0},
+	}
+	sc := supercluster.New(supercluster.DefaultOptions[int]()).Load(points)
+
+	nonCrossing := sc.GetClusters([4]float64{-179, -10, -177, 10}, 1)
+	crossing := sc.GetClusters([4]float64{179, -10, -177, 10}, 1)
+	assert.NotEmpty(t, nonCrossing)
+	assert.NotEmpty(t, crossing)
+	assert.Equal(t, len(crossing), len(nonCrossing)+1, "it should include points on both side of antimeridian")
+
+	assert.Equal(t, len(sc.GetClusters([4]float64{-180, -90, 180, 90}, 17)), len(points))
+	assert.Equal(t, len(sc.GetClusters([4]float64{-200, -90, 200, 90}, 17)), len(points), "bounding box wider than world")
+}
+
+// Test GetChildren, GetLeaves & GetClusterExpansionZoom
+func TestHierarchy(t *testing.T) {
+	points := randomPoints(2_000)
+	sc := supercluster.New(supercluster.DefaultOptions[int]()).Load(points)
+
+	for z := 0; z <= 16; z += 4 {
+		for _, c := range sc.GetClusters(world, z) {
+			if !c.Cluster {
+				continue
+			}
+
+			children, err := sc.GetChildren(c.ID)
+			assert.Nil(t, err)
+			total := 0
+			for _, child := range children {
+				total += child.NumPoints
+			}
+			assert.Equal(t, total, c.NumPoints, "children should cover all points of cluster")
+
+			leaves, err := sc.GetLeaves(c.ID, -1, 0)
+			assert.Nil(t, err)
+			assert.Equal(t, len(leaves), c.NumPoints, "leaves should be all points of cluster")
+
+			expansionZoom, err := sc.GetClusterExpansionZoom(c.ID)
+			assert.Nil(t, err)
+			assert.Greater(t, expansionZoom, z)
+			assert.LessOrEqual(t, expansionZoom, 17)
+		}
+	}
+
+	_, err := sc.GetChildren(0)
+	assert.Equal(t, err, supercluster.ErrClusterNotFound, "point is not a cluster")
+	_, err = sc.GetChildren(1 << 30)
+	assert.Equal(t, err, supercluster.ErrClusterNotFound)
+	_, err = sc.GetLeaves(1<<30, 10, 0)
+	assert.Equal(t, err, supercluster.ErrClusterNotFound)
+	_, err = sc.GetClusterExpansionZoom(1 << 30)
+	assert.Equal(t, err, supercluster.ErrClusterNotFound)
+}
+
+// Test GetLeaves pagination
+func TestGetLeaves(t *testing.T) {
+	points := randomPoints(2_000)
+	sc := supercluster.New(supercluster.DefaultOptions[int]()).Load(points)
+
+	var biggest supercluster.Cluster[int]
+	for _, c := range sc.GetClusters(world, 0) {
+		if c.NumPoints > biggest.NumPoints {
+			biggest = c
+		}
+	}
+
+	all, err := sc.GetLeaves(biggest.ID, -1, 0)
+	assert.Nil(t, err)
+
+	pages := []supercluster.Cluster[int]{}
+	for offset := 0; offset < biggest.NumPoints; offset += 10 {
+		page, err := sc.GetLeaves(biggest.ID, 10, offset)
+		assert.Nil(t, err)
+		assert.LessOrEqual(t, len(page), 10)
+		pages = append(pages, page...)
+	}
+	assert.Equal(t, pages, all, "pages should be same with all leaves")
+
+	empty, err := sc.GetLeaves(biggest.ID, 0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, empty, []supercluster.Cluster[int]{}, "zero limit should return no leaves")
+	_, err = sc.GetLeaves(1<<30, 0, 0)
+	assert.Equal(t, err, supercluster.ErrClusterNotFound)
+}
+
+// Test Map & Reduce aggregate properties
+func TestMapReduce(t *testing.T) {
+	points := randomPoints(2_000)
+
+	options := supercluster.DefaultOptions[int]()
+	options.Map = func(index int) int { return index }
+	options.Reduce = func(acc, props int) int { return acc + props }
+	sc := supercluster.New(options).Load(points)
+
+	for z := 0; z <= 16; z += 4 {
+		for _, c := range sc.GetClusters(world, z) {
+			if !c.Cluster {
+				assert.Equal(t, c.Properties, c.ID, "single point should have mapped properties")
+				continue
+			}
+			leaves, _ := sc.GetLeaves(c.ID, -1, 0)
+			sum := 0
+			for _, leaf := range leaves {
+				sum += leaf.ID
+			}
+			assert.Equal(t, c.Properties, sum, "cluster should have reduced properties of its points")
+		}
+	}
+}
+
+// Test options of min points & zoom levels
+func TestOptions(t *testing.T) {
+	points := randomPoints(2_000)
+
+	options := supercluster.DefaultOptions[int]()
+	options.MinPoints = 5
+	options.MinZoom = 2
+	options.MaxZoom = 10
+	sc := supercluster.New(options).Load(points)
+
+	for _, c := range sc.GetClusters(world, 5) {
+		if c.Cluster {
+			assert.GreaterOrEqual(t, c.NumPoints, 5)
+		}
+	}
+	assert.Equal(t, sc.GetClusters(world, 0), sc.GetClusters(world, 2), "zoom below min zoom should be limited")
+	assert.Equal(t, len(sc.GetClusters(world, 11)), len(points))
+
+	empty := supercluster.New(supercluster.DefaultOptions[int]())
+	assert.Equal(t, empty.GetClusters(world, 0), []supercluster.Cluster[int]{})
+	_, err := empty.GetChildren(0)
+	assert.Equal(t, err, supercluster.ErrClusterNotFound)
+}
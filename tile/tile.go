@@ -0,0 +1,91 @@
+// Package tile implements XYZ tile queries of locations projected into Web Mercator, e.g. for serving points as vector tiles
+package tile
+
+import (
+	"math"
+
+	"github.com/raditzlawliet/kdbush"
+)
+
+// STANDARD_EXTENT default extent of vector tile
+const STANDARD_EXTENT = 4096
+
+// Index KDBush of locations projected into Web Mercator unit square, x from west to east and y from north to south in [0..1]
+type Index struct {
+	bush *kdbush.KDBush
+}
+
+// Feature point inside tile with tile-local integer coordinates
+type Feature struct {
+	// ID index of the input point
+	ID int
+	// X & Y tile-local coordinates in [0..extent], outside it within the buffer
+	X int
+	Y int
+}
+
+// NewIndex project locations into Web Mercator and build the index, [kdbush.Point] X & Y are longitude & latitude
+func NewIndex(points []kdbush.Point, nodeSize int) *Index {
+	coords := make([]float64, 2*len(points))
+	for i, p := range points {
+		coords[2*i], coords[2*i+1] = Project(p.GetX(), p.GetY())
+	}
+
+	return &Index{kdbush.NewBush().BuildIndexFromCoordsInPlace(coords, nodeSize)}
+}
+
+// Tile returns points inside tile z/x/y with tile-local coordinates of given extent, including points within buffer around the tile.
+// Buffer on the west & east edge of the world wraps across the antimeridian
+func (ix *Index) Tile(z, x, y int, extent, buffer int) []Feature {
+	result := []Feature{}
+	if z < 0 || z > 30 {
+		return result
+	}
+
+	z2 := float64(int(1) << z)
+	p := float64(buffer) / float64(extent)
+	top := (float64(y) - p) / z2
+	bottom := (float64(y) + 1 + p) / z2
+
+	ix.appendFeatures(&result, (float64(x)-p)/z2, top, (float64(x)+1+p)/z2, bottom, z2, float64(x), float64(y), float64(extent))
+
+	// buffer across the antimeridian, take points from the other edge of the world
+	if x == 0 {
+		ix.appendFeatures(&result, 1-p/z2, top, 1, bottom, z2, z2, float64(y), float64(extent))
+	}
+	if x == int(z2)-1 {
+		ix.appendFeatures(&result, 0, top, p/z2, bottom, z2, -1, float64(y), float64(extent))
+	}
+
+	return result
+}
+
+// GetBush return the index of projected locations
+func (ix *Index) GetBush() *kdbush.KDBush {
+	return ix.bush
+}
+
+// appendFeatures append points inside range with coordinates relative to tile x & y
+func (ix *Index) appendFeatures(result *[]Feature, minX, minY, maxX, maxY float64, z2, x, y, extent float64) {
+	ix.bush.RangeFunc(minX, minY, maxX, maxY, func(id int, px, py float64) bool {
+		*result = append(*result, Feature{
+			ID: id,
+			X:  int(math.Round(extent * (px*z2 - x))),
+			Y:  int(math.Round(extent * (py*z2 - y))),
+		})
+		return true
+	})
+}
+
+// Project project longitude & latitude into Web Mercator unit square, latitude beyond ±85.05° is clamped to the edge
+func Project(lng, lat float64) (x, y float64) {
+	sin := math.Sin(lat * math.Pi / 180)
+	y = 0.5 - 0.25*math.Log((1+sin)/(1-sin))/math.Pi
+	return lng/360 + 0.5, math.Max(0, math.Min(1, y))
+}
+
+// Unproject unproject Web Mercator unit square into longitude & latitude
+func Unproject(x, y float64) (lng, lat float64) {
+	y2 := (180 - y*360) * math.Pi / 180
+	return (x - 0.5) * 360, 360*math.Atan(math.Exp(y2))/math.Pi - 90
+}
@@ -0,0 +1,97 @@
+package tile_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/raditzlawliet/kdbush/geo"
+	"github.com/raditzlawliet/kdbush/tile"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test Project & Unproject
+func TestProject(t *testing.T) {
+	x, y := tile.Project(0, 0)
+	assert.Equal(t, x, 0.5)
+	assert.InDelta(t, y, 0.5, 1e-12)
+
+	x, y = tile.Project(-180, 85.0511287798066)
+	assert.Equal(t, x, 0.0)
+	assert.InDelta(t, y, 0, 1e-9)
+
+	_, y = tile.Project(0, 90)
+	assert.Equal(t, y, 0.0, "latitude should be clamped")
+	_, y = tile.Project(0, -90)
+	assert.Equal(t, y, 1.0, "latitude should be clamped")
+
+	lng, lat := tile.Unproject(tile.Project(112.75, -7.26))
+	assert.InDelta(t, lng, 112.75, 1e-9)
+	assert.InDelta(t, lat, -7.26, 1e-9)
+}
+
+// Test Tile against projection of each point
+func TestTile(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	points := []kdbush.Point{}
+	for i := 0; i < 10_000; i++ {
+		points = append(points, &geo.MarkerPoint{Lng: rng.Float64()*360 - 180, Lat: rng.Float64()*170 - 85})
+	}
+	index := tile.NewIndex(points, kdbush.STANDARD_NODE_SIZE)
+
+	for _, z := range []int{0, 1, 3, 5} {
+		z2 := 1 << z
+		for q := 0; q < 10; q++ {
+			x, y := rng.Intn(z2), rng.Intn(z2)
+
+			// point near the other edge of the world wraps across the antimeridian
+			shifts := []float64{0}
+			if x == 0 {
+				shifts = append(shifts, -1)
+			}
+			if x == z2-1 {
+				shifts = append(shifts, 1)
+			}
+
+			expected := []tile.Feature{}
+			for i, p := range points {
+				px, py := tile.Project(p.GetX(), p.GetY())
+				for _, shift := range shifts {
+					fx := 4096 * ((px+shift)*float64(z2) - float64(x))
+					fy := 4096 * (py*float64(z2) - float64(y))
+					if fx >= -64 && fx <= 4096+64 && fy >= -64 && fy <= 4096+64 {
+						expected = append(expected, tile.Feature{ID: i, X: int(math.Round(fx)), Y: int(math.Round(fy))})
+					}
+				}
+			}
+
+			assert.Equal(t, countFeatures(index.Tile(z, x, y, tile.STANDARD_EXTENT, 64)), countFeatures(expected), "it should be same with projection of each point")
+		}
+	}
+}
+
+// Test Tile buffer wraps across the antimeridian
+func TestTileAntimeridian(t *testing.T) {
+	index := tile.NewIndex([]kdbush.Point{
+		&geo.MarkerPoint{Lng: 179.9, Lat: 0},
+		&geo.MarkerPoint{Lng: -179.9, Lat: 0},
+		&geo.MarkerPoint{Lng: 0, Lat: 0},
+	}, kdbush.STANDARD_NODE_SIZE)
+
+	// tile 1/0/1 is the south west of the world, point on the east edge appears on the west buffer
+	assert.ElementsMatch(t, index.Tile(1, 0, 0, 4096, 64), []tile.Feature{{ID: 1, X: 2, Y: 4096}, {ID: 0, X: -2, Y: 4096}, {ID: 2, X: 4096, Y: 4096}})
+	assert.ElementsMatch(t, index.Tile(1, 1, 0, 4096, 64), []tile.Feature{{ID: 0, X: 4094, Y: 4096}, {ID: 1, X: 4098, Y: 4096}, {ID: 2, X: 0, Y: 4096}})
+	assert.ElementsMatch(t, index.Tile(1, 0, 0, 4096, 0), []tile.Feature{{ID: 1, X: 2, Y: 4096}, {ID: 2, X: 4096, Y: 4096}}, "it should not wrap without buffer")
+	assert.Equal(t, index.Tile(-1, 0, 0, 4096, 64), []tile.Feature{})
+}
+
+// countFeatures return number of occurrence of each feature, cheaper than ElementsMatch on many features
+func countFeatures(features []tile.Feature) map[tile.Feature]int {
+	result := map[tile.Feature]int{}
+	for _, f := range features {
+		result[f]++
+	}
+	return result
+}
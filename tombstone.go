@@ -0,0 +1,171 @@
+package kdbush
+
+import "math/bits"
+
+// STANDARD_COMPACT_RATIO default ratio of deleted points to rebuild the index on [KDBushOf.Compact]
+const STANDARD_COMPACT_RATIO = 0.25
+
+// Delete mark point with given id as deleted, so it will be skipped by all queries.
+// Deleted points still take space until [KDBushOf.Compact] or [KDBushOf.Finish] rebuild the index.
+// Return false if the id does not exist or already deleted.
+// Delete is not safe to be called concurrently with queries
+func (kd *KDBushOf[C, I]) Delete(id I) bool {
+	if kd.inverse == nil && kd.positions == nil {
+		kd.indexPositions()
+	}
+
+	i := kd.positionOf(id)
+	if i < 0 || kd.DeletedAt(i) {
+		return false
+	}
+
+	if len(kd.deleted) <= i>>6 {
+		deleted := make([]uint64, (len(kd.ids)+63)>>6)
+		copy(deleted, kd.deleted)
+		kd.deleted = deleted
+	}
+	kd.deleted[i>>6] |= 1 << (uint(i) & 63)
+	kd.deletedCount++
+	return true
+}
+
+// DeletedAt return point at position i of [KDBushOf.GetIndexes] is deleted or not
+func (kd *KDBushOf[C, I]) DeletedAt(i int) bool {
+	return kd.deletedCount > 0 && i>>6 < len(kd.deleted) && kd.deleted[i>>6]&(1<<(uint(i)&63)) != 0
+}
+
+// Live return number of points that not deleted
+func (kd *KDBushOf[C, I]) Live() int {
+	return len(kd.ids) - kd.deletedCount
+}
+
+// Deleted return number of deleted points that still take space in the index
+func (kd *KDBushOf[C, I]) Deleted() int {
+	return kd.deletedCount
+}
+
+// SetCompactRatio set ratio of deleted points to rebuild the index on [KDBushOf.Compact], default is [STANDARD_COMPACT_RATIO]
+func (kd *KDBushOf[C, I]) SetCompactRatio(ratio float64) *KDBushOf[C, I] {
+	kd.compactRatio = ratio
+	return kd
+}
+
+// Compact rebuild the index without deleted points once ratio of deleted points reach the compact ratio.
+// Indexes of remaining points are kept. Return true if the index is rebuilt
+func (kd *KDBushOf[C, I]) Compact() bool {
+	if !kd.indexed || kd.deletedCount == 0 || float64(kd.deletedCount) < kd.compactRatio*float64(len(kd.ids)) {
+		return false
+	}
+
+	kd.Finish()
+	return true
+}
+
+// indexPositions build lookup of position by id. Ids are dense when all of them are within 0 to twice the number of points,
+// e.g. [KDBushOf.BuildIndex], builder & columnar, then inverse permutation is used instead of map
+func (kd *KDBushOf[C, I]) indexPositions() {
+	size := kd.nextID
+	if size < 0 {
+		// unknown, e.g. loaded or BuildIndexWithIDs
+		size = 0
+		for _, v := range kd.ids {
+			if v < 0 || uint64(v) >= uint64(2*len(kd.ids)) {
+				size = -1
+				break
+			}
+			size = max(size, int(v)+1)
+		}
+	}
+
+	if size >= 0 && size <= 2*len(kd.ids) {
+		kd.inverse = make([]int, size)
+		for i := range kd.inverse {
+			kd.inverse[i] = -1
+		}
+		for i, v := range kd.ids {
+			kd.inverse[v] = i
+		}
+		return
+	}
+
+	kd.positions = make(map[I]int, len(kd.ids))
+	for i, v := range kd.ids {
+		kd.positions[v] = i
+	}
+}
+
+// positionOf return position of point with given id, or -1 if it does not exist
+func (kd *KDBushOf[C, I]) positionOf(id I) int {
+	if kd.inverse != nil {
+		if id < 0 || uint64(id) >= uint64(len(kd.inverse)) {
+			return -1
+		}
+		return kd.inverse[id]
+	}
+
+	i, ok := kd.positions[id]
+	if !ok {
+		return -1
+	}
+	return i
+}
+
+// setPosition update lookup of position by id after [KDBushOf.Add], if it is built
+func (kd *KDBushOf[C, I]) setPosition(id, i int) {
+	if kd.inverse != nil {
+		for len(kd.inverse) <= id {
+			kd.inverse = append(kd.inverse, -1)
+		}
+		kd.inverse[id] = i
+	} else if kd.positions != nil {
+		kd.positions[I(id)] = i
+	}
+}
+
+// resetDeleted clear all tombstones
+func (kd *KDBushOf[C, I]) resetDeleted() {
+	kd.deleted = nil
+	kd.deletedCount = 0
+	kd.inverse = nil
+	kd.positions = nil
+}
+
+// removeDeleted remove deleted points from ids & coords and clear all tombstones
+func (kd *KDBushOf[C, I]) removeDeleted() {
+	if kd.deletedCount > 0 {
+		n := 0
+		for i := range kd.ids {
+			if !kd.DeletedAt(i) {
+				kd.ids[n] = kd.ids[i]
+				kd.coords[2*n] = kd.coords[2*i]
+				kd.coords[2*n+1] = kd.coords[2*i+1]
+				n++
+			}
+		}
+		kd.ids = kd.ids[:n]
+		kd.coords = kd.coords[:2*n]
+	}
+
+	kd.resetDeleted()
+}
+
+// deletedBetween return number of deleted points at position left to right inclusively
+func (kd *KDBushOf[C, I]) deletedBetween(left, right int) int {
+	if kd.deletedCount == 0 || left > right {
+		return 0
+	}
+
+	right = min(right, 64*len(kd.deleted)-1)
+	count := 0
+	for w := left >> 6; w <= right>>6; w++ {
+		word := kd.deleted[w]
+		if w == left>>6 {
+			word &= ^uint64(0) << (uint(left) & 63)
+		}
+		if w == right>>6 {
+			word &= ^uint64(0) >> (63 - uint(right)&63)
+		}
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
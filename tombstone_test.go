@@ -0,0 +1,129 @@
+package kdbush_test
+
+import (
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test Delete skip deleted points on queries
+func TestDelete(t *testing.T) {
+	for _, nodeSize := range []int{kdbush.STANDARD_NODE_SIZE, 4} {
+		bush := kdbush.NewBush().BuildIndex(points, nodeSize)
+
+		// delete all points on x=0
+		deleted := []int{}
+		for i, p := range points {
+			if p.GetX() == 0 {
+				assert.True(t, bush.Delete(i), "it should be deleted")
+				deleted = append(deleted, i)
+			}
+		}
+		assert.False(t, bush.Delete(deleted[0]), "it should be already deleted")
+		assert.False(t, bush.Delete(len(points)), "it should be not exist")
+		assert.Equal(t, bush.Live(), len(points)-21)
+		assert.Equal(t, bush.Deleted(), 21)
+
+		for _, id := range bush.Range(-10, -10, 10, 10) {
+			assert.NotContains(t, deleted, id, "it should skip deleted points")
+		}
+		assert.Equal(t, len(bush.Range(-10, -10, 10, 10)), len(points)-21)
+		assert.ElementsMatch(t, bush.Range(-2.1, 0, 2.1, 0), []int{indexOf(-2, 0), indexOf(-1, 0), indexOf(1, 0), indexOf(2, 0)})
+		assert.ElementsMatch(t, bush.Within(0, 0, 1), []int{indexOf(-1, 0), indexOf(1, 0)})
+		assert.ElementsMatch(t, bush.Nearest(0, 0, 2, -1, nil), []int{indexOf(-1, 0), indexOf(1, 0)})
+		assert.Equal(t, len(bush.Polygon(squareWithHole)), 11*11-5*5-6)
+
+		count := 0
+		for i := range bush.GetIndexes() {
+			if bush.DeletedAt(i) {
+				count++
+			}
+		}
+		assert.Equal(t, count, 21)
+	}
+}
+
+// Test Compact rebuild index without deleted points and keep indexes
+func TestCompact(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, 4)
+
+	assert.False(t, bush.Compact(), "it should not compact without deleted points")
+
+	bush.Delete(indexOf(0, 0))
+	assert.False(t, bush.Compact(), "it should not compact below compact ratio")
+	_, err := bush.MarshalBinary()
+	assert.Equal(t, err, kdbush.ErrHasDeleted)
+
+	assert.True(t, bush.SetCompactRatio(0).Compact(), "it should compact")
+	assert.Equal(t, bush.Deleted(), 0)
+	assert.Equal(t, bush.Live(), len(points)-1)
+	assert.Equal(t, len(bush.GetIndexes()), len(points)-1)
+	assert.ElementsMatch(t, bush.Within(0, 0, 1), []int{indexOf(-1, 0), indexOf(1, 0), indexOf(0, -1), indexOf(0, 1)})
+
+	// delete more than standard compact ratio
+	bush.SetCompactRatio(kdbush.STANDARD_COMPACT_RATIO)
+	for i := 0; i < len(points)/4; i++ {
+		bush.Delete(i)
+	}
+	assert.True(t, bush.Compact(), "it should compact")
+	for _, id := range bush.Range(-10, -10, 10, 10) {
+		assert.GreaterOrEqual(t, id, len(points)/4, "indexes should be kept")
+		assert.Equal(t, bush.Range(points[id].GetX(), points[id].GetY(), points[id].GetX(), points[id].GetY()), []int{id})
+	}
+
+	// add after compact should not reuse index
+	id := bush.Add(100, 100)
+	assert.Equal(t, id, len(points))
+	bush.Finish()
+	assert.Equal(t, bush.Within(100, 100, 1), []int{id})
+	assert.True(t, bush.Delete(id))
+	assert.Equal(t, bush.Within(100, 100, 1), []int{})
+}
+
+// Test Delete with dense, sparse & negative ids, and ids added after delete
+func TestDeleteWithIDs(t *testing.T) {
+	sparse := make([]uint64, len(points))
+	negative := make([]int, len(points))
+	for i := range points {
+		sparse[i] = uint64(1)<<63 + uint64(i)*1e12
+		negative[i] = -i - 1
+	}
+
+	sparseBush := kdbush.NewBushOf[float64, uint64]().BuildIndexWithIDs(points, sparse, 4)
+	assert.True(t, sparseBush.Delete(sparse[indexOf(0, 0)]))
+	assert.False(t, sparseBush.Delete(0), "it should be not exist")
+	assert.Equal(t, sparseBush.Within(0, 0, 0.5), []uint64{})
+
+	negativeBush := kdbush.NewBush().BuildIndexWithIDs(points, negative, 4)
+	assert.True(t, negativeBush.Delete(negative[indexOf(0, 0)]))
+	assert.False(t, negativeBush.Delete(0), "it should be not exist")
+	assert.Equal(t, negativeBush.Within(0, 0, 0.5), []int{})
+
+	// added points can be deleted before finish
+	bush := kdbush.NewBushOf[float32, uint16]().BuildIndex(points, 4)
+	assert.True(t, bush.Delete(uint16(indexOf(0, 0))))
+	id := bush.Add(100, 100)
+	assert.True(t, bush.Delete(uint16(id)))
+	assert.False(t, bush.Delete(uint16(id+1)), "it should be not exist")
+	bush.Finish()
+	assert.Equal(t, bush.Live(), len(points)-1)
+	assert.Equal(t, bush.Within(100, 100, 1), []uint16{})
+
+	// loaded index keep ids
+	data, err := kdbush.NewBushOf[float32, uint16]().BuildIndex(points, 4).MarshalBinary()
+	assert.Nil(t, err)
+	loaded := kdbush.NewBushOf[float32, uint16]()
+	assert.Nil(t, loaded.UnmarshalBinary(data))
+	assert.True(t, loaded.Delete(uint16(indexOf(0, 0))))
+	assert.Equal(t, loaded.Within(0, 0, 0.5), []uint16{})
+}
+
+func indexOf(x, y float64) int {
+	for i, p := range points {
+		if p.GetX() == x && p.GetY() == y {
+			return i
+		}
+	}
+	return -1
+}
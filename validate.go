@@ -0,0 +1,126 @@
+package kdbush
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// ErrInvalidNodeSize returned when nodeSize is 0 or less
+var ErrInvalidNodeSize = errors.New("kdbush: nodeSize must be greater than 0")
+
+// ErrTooManyPoints returned when indexes of the points can't fit in the index type, e.g. more than 65536 points for uint16
+var ErrTooManyPoints = errors.New("kdbush: number of points can't fit in the index type")
+
+// ErrInvalidCoordinate returned when point has NaN or Inf coordinate, or it can't fit in the coordinate type
+type ErrInvalidCoordinate struct {
+	// Index of the point
+	Index int
+}
+
+func (e ErrInvalidCoordinate) Error() string {
+	return fmt.Sprintf("kdbush: point %d has invalid coordinate", e.Index)
+}
+
+// ErrNilPoint returned when point is nil
+type ErrNilPoint struct {
+	// Index of the point
+	Index int
+}
+
+func (e ErrNilPoint) Error() string {
+	return fmt.Sprintf("kdbush: point %d is nil", e.Index)
+}
+
+// TryBuildIndex same as [KDBushOf.BuildIndex], but validate nodeSize & all points first.
+// It returns [ErrInvalidNodeSize], [ErrTooManyPoints], [ErrInvalidCoordinate] or [ErrNilPoint] of the first invalid point,
+// and the index is not modified
+func (kd *KDBushOf[C, I]) TryBuildIndex(points []Point, nodeSize int) (*KDBushOf[C, I], error) {
+	if nodeSize <= 0 {
+		return nil, ErrInvalidNodeSize
+	}
+	if len(points) > 0 && !fitsID[I](uint64(len(points)-1)) {
+		return nil, ErrTooManyPoints
+	}
+
+	for i, v := range points {
+		if err := validatePoint[C](i, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return kd.BuildIndex(points, nodeSize), nil
+}
+
+// TryBuildIndexSkipInvalid same as [KDBushOf.TryBuildIndex], but skip invalid points instead of failing.
+// Indexes of the valid points are kept same with their position in points.
+// It returns error of each skipped point, and fails only on [ErrInvalidNodeSize] or [ErrTooManyPoints]
+func (kd *KDBushOf[C, I]) TryBuildIndexSkipInvalid(points []Point, nodeSize int) (*KDBushOf[C, I], []error, error) {
+	if nodeSize <= 0 {
+		return nil, nil, ErrInvalidNodeSize
+	}
+	if len(points) > 0 && !fitsID[I](uint64(len(points)-1)) {
+		return nil, nil, ErrTooManyPoints
+	}
+
+	kd.reset(nodeSize)
+
+	kd.ids = make([]I, 0, len(points))
+	kd.coords = make([]C, 0, 2*len(points))
+	kd.nextID = len(points)
+
+	skipped := []error{}
+	for i, v := range points {
+		if err := validatePoint[C](i, v); err != nil {
+			skipped = append(skipped, err)
+			continue
+		}
+
+		kd.ids = append(kd.ids, I(i))
+		kd.coords = append(kd.coords, C(v.GetX()), C(v.GetY()))
+	}
+
+	return kd.Finish(), skipped, nil
+}
+
+// validatePoint return error if point at index i is nil or has invalid coordinate for C
+func validatePoint[C Coord](i int, p Point) error {
+	if p == nil {
+		return ErrNilPoint{i}
+	}
+	if v := reflect.ValueOf(p); v.Kind() == reflect.Ptr && v.IsNil() {
+		return ErrNilPoint{i}
+	}
+	if !validCoord[C](p.GetX()) || !validCoord[C](p.GetY()) {
+		return ErrInvalidCoordinate{i}
+	}
+	return nil
+}
+
+// validCoord return v is a finite number that fit in C
+func validCoord[C Coord](v float64) bool {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return false
+	}
+
+	var c C
+	switch any(c).(type) {
+	case int8:
+		return v >= math.MinInt8 && v <= math.MaxInt8
+	case uint8:
+		return v >= 0 && v <= math.MaxUint8
+	case int16:
+		return v >= math.MinInt16 && v <= math.MaxInt16
+	case uint16:
+		return v >= 0 && v <= math.MaxUint16
+	case int32:
+		return v >= math.MinInt32 && v <= math.MaxInt32
+	case uint32:
+		return v >= 0 && v <= math.MaxUint32
+	case float32:
+		return math.Abs(v) <= math.MaxFloat32
+	default:
+		return true
+	}
+}
@@ -0,0 +1,89 @@
+package kdbush_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test TryBuildIndex validate nodeSize & points
+func TestTryBuildIndex(t *testing.T) {
+	bush, err := kdbush.NewBush().TryBuildIndex(points, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, bush.GetIndexes(), kdbush.NewBush().BuildIndex(points, 4).GetIndexes())
+
+	_, err = kdbush.NewBush().TryBuildIndex(points, 0)
+	assert.Equal(t, err, kdbush.ErrInvalidNodeSize)
+
+	var nilPoint *kdbush.SimplePoint
+	testCases := []struct {
+		Points []kdbush.Point
+		Err    error
+	}{
+		{[]kdbush.Point{&kdbush.SimplePoint{0, 0}, &kdbush.SimplePoint{math.NaN(), 0}}, kdbush.ErrInvalidCoordinate{Index: 1}},
+		{[]kdbush.Point{&kdbush.SimplePoint{0, math.Inf(-1)}}, kdbush.ErrInvalidCoordinate{Index: 0}},
+		{[]kdbush.Point{&kdbush.SimplePoint{0, 0}, nil}, kdbush.ErrNilPoint{Index: 1}},
+		{[]kdbush.Point{&kdbush.SimplePoint{0, 0}, &kdbush.SimplePoint{0, 0}, nilPoint}, kdbush.ErrNilPoint{Index: 2}},
+	}
+
+	for _, testCase := range testCases {
+		bush := kdbush.NewBush().BuildIndex(points, 4)
+		result, err := bush.TryBuildIndex(testCase.Points, 4)
+		assert.Nil(t, result)
+		assert.Equal(t, err, testCase.Err)
+		assert.Equal(t, len(bush.GetIndexes()), len(points), "it should not modify the index")
+	}
+
+	// coordinate can't fit in the type
+	_, err = kdbush.NewBushOf[int8, uint16]().TryBuildIndex([]kdbush.Point{&kdbush.SimplePoint{0, 0}, &kdbush.SimplePoint{200, 0}}, 4)
+	var invalidCoordinate kdbush.ErrInvalidCoordinate
+	assert.True(t, errors.As(err, &invalidCoordinate))
+	assert.Equal(t, invalidCoordinate.Index, 1)
+	assert.EqualError(t, err, "kdbush: point 1 has invalid coordinate")
+
+	// indexes can't fit in the index type
+	many := make([]kdbush.Point, 70000)
+	for i := range many {
+		many[i] = &kdbush.SimplePoint{X: float64(i), Y: float64(i)}
+	}
+	_, err = kdbush.NewBushOf[float32, uint16]().TryBuildIndex(many, 64)
+	assert.Equal(t, err, kdbush.ErrTooManyPoints)
+	_, err = kdbush.NewBushOf[float32, uint16]().TryBuildIndex(many[:65536], 64)
+	assert.Nil(t, err)
+}
+
+// Test TryBuildIndexSkipInvalid skip invalid points and keep indexes
+func TestTryBuildIndexSkipInvalid(t *testing.T) {
+	_points := []kdbush.Point{
+		&kdbush.SimplePoint{0, 0},
+		&kdbush.SimplePoint{math.NaN(), 1},
+		nil,
+		&kdbush.SimplePoint{1, 1},
+		&kdbush.SimplePoint{2, math.Inf(1)},
+		&kdbush.SimplePoint{2, 2},
+	}
+
+	bush, skipped, err := kdbush.NewBush().TryBuildIndexSkipInvalid(_points, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, skipped, []error{
+		kdbush.ErrInvalidCoordinate{Index: 1},
+		kdbush.ErrNilPoint{Index: 2},
+		kdbush.ErrInvalidCoordinate{Index: 4},
+	})
+	assert.Equal(t, bush.Live(), 3)
+	assert.ElementsMatch(t, bush.Range(-10, -10, 10, 10), []int{0, 3, 5})
+	assert.Equal(t, bush.Add(3, 3), len(_points))
+
+	_, _, err = kdbush.NewBush().TryBuildIndexSkipInvalid(_points, -1)
+	assert.Equal(t, err, kdbush.ErrInvalidNodeSize)
+
+	many := make([]kdbush.Point, 70000)
+	for i := range many {
+		many[i] = &kdbush.SimplePoint{X: float64(i), Y: float64(i)}
+	}
+	_, _, err = kdbush.NewBushOf[float32, uint16]().TryBuildIndexSkipInvalid(many, 64)
+	assert.Equal(t, err, kdbush.ErrTooManyPoints)
+}
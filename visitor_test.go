@@ -0,0 +1,101 @@
+package kdbush_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/raditzlawliet/kdbush"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test RangeFunc & RangeAppend give same result with Range
+func TestRangeFunc(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, 4)
+
+	for _, q := range [][4]float64{{-2.1, 0, 2.1, 0}, {-2.1, 1, 2.1, 2}, {-10, -10, 10, 10}, {20, 20, 30, 30}} {
+		expected := bush.Range(q[0], q[1], q[2], q[3])
+
+		result := []int{}
+		bush.RangeFunc(q[0], q[1], q[2], q[3], func(id int, x, y float64) bool {
+			assert.Equal(t, x, points[id].GetX(), "x should be same with point")
+			assert.Equal(t, y, points[id].GetY(), "y should be same with point")
+			result = append(result, id)
+			return true
+		})
+		assert.Equal(t, result, expected)
+
+		dst := []int{-1}
+		assert.Equal(t, bush.RangeAppend(dst, q[0], q[1], q[2], q[3]), append([]int{-1}, expected...))
+	}
+
+	// stop early
+	count := 0
+	bush.RangeFunc(-10, -10, 10, 10, func(id int, x, y float64) bool {
+		count++
+		return count < 3
+	})
+	assert.Equal(t, count, 3, "it should stop after return false")
+
+	// not indexed
+	kdbush.NewBush().RangeFunc(-10, -10, 10, 10, func(id int, x, y float64) bool {
+		t.Error("it should not called")
+		return true
+	})
+	assert.Equal(t, kdbush.NewBush().RangeAppend(nil, -10, -10, 10, 10), []int(nil))
+}
+
+// Test WithinFunc & WithinAppend give same result with Within
+func TestWithinFunc(t *testing.T) {
+	bush := kdbush.NewBush().BuildIndex(points, 4)
+
+	for _, q := range [][3]float64{{0, 0, 1}, {0.3, 0.2, 0.8}, {5, 5, 4.5}, {30, 30, 1}} {
+		expected := bush.Within(q[0], q[1], q[2])
+
+		result := []int{}
+		bush.WithinFunc(q[0], q[1], q[2], func(id int, x, y float64) bool {
+			assert.Equal(t, x, points[id].GetX(), "x should be same with point")
+			assert.Equal(t, y, points[id].GetY(), "y should be same with point")
+			result = append(result, id)
+			return true
+		})
+		assert.Equal(t, result, expected)
+
+		dst := make([]int, 0, len(points))
+		assert.Equal(t, bush.WithinAppend(dst, q[0], q[1], q[2]), expected)
+	}
+
+	// stop early
+	count := 0
+	bush.WithinFunc(0, 0, 5, func(id int, x, y float64) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, count, 1, "it should stop after return false")
+}
+
+// Test RangeFunc, RangeAppend, WithinFunc & WithinAppend do not allocate
+func TestVisitorAllocation(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+
+	_points := []kdbush.Point{}
+	for i := 0; i < 100_000; i++ {
+		_points = append(_points, &kdbush.SimplePoint{rng.Float64()*24.0 + 24.0, rng.Float64()*24.0 + 24.0})
+	}
+	bush := kdbush.NewBush().BuildIndex(_points, 8)
+
+	count := 0
+	dst := make([]int, 0, len(_points))
+	allocs := testing.AllocsPerRun(100, func() {
+		bush.RangeFunc(30, 30, 35, 35, func(id int, x, y float64) bool {
+			count++
+			return true
+		})
+		bush.WithinFunc(30, 30, 5, func(id int, x, y float64) bool {
+			count++
+			return true
+		})
+		dst = bush.RangeAppend(dst[:0], 30, 30, 35, 35)
+		dst = bush.WithinAppend(dst[:0], 30, 30, 5)
+	})
+	assert.Equal(t, allocs, 0.0, "it should not allocate")
+}